@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"kastelo.dev/syncthing-configd/internal/expiry"
+)
+
+type expiryCmd struct {
+	Extend string        `help:"Postpone the expiry of the given device" placeholder:"DEVICE-ID"`
+	By     time.Duration `help:"How long to postpone the expiry by (with --extend)" default:"24h"`
+	JSON   bool          `help:"Print the result as JSON"`
+}
+
+func (c expiryCmd) Run(cli *CLI) error {
+	client, err := adminClient(cli)
+	if err != nil {
+		return err
+	}
+
+	var exps []expiry.Expiry
+	if c.Extend != "" {
+		query := url.Values{"device": {c.Extend}, "by": {c.By.String()}}
+		if err := client.Post("/rest/expiry/extend", query, &exps); err != nil {
+			return err
+		}
+	} else if err := client.Get("/rest/expiry", nil, &exps); err != nil {
+		return err
+	}
+
+	if c.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(exps)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INSTANCE\tDEVICE\tNAME\tPATTERN\tEXPIRES\tPAUSED")
+	for _, exp := range exps {
+		paused := "no"
+		if exp.Paused {
+			paused = "yes"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", exp.Instance.Short(), exp.Device, exp.Name, exp.Pattern, exp.Expires.Local().Format(time.DateTime), paused)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/gc"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+type gcCmd struct {
+	Instance string `help:"Syncthing instance address, as given in the configuration"`
+	Override bool   `help:"Allow a run blocked by the safety limits to proceed, and start it now; with --apply, apply regardless of the limits"`
+	RunNow   bool   `name:"run" help:"Start a run now, on the given instance or all instances"`
+	Report   bool   `help:"Connect to the instance and show what a run would remove right now"`
+	JSON     bool   `help:"Print the report as JSON"`
+	Apply    bool   `help:"Have the running daemon perform the removals in the report (with --report)"`
+}
+
+func (c gcCmd) Run(cli *CLI, l *slog.Logger) error {
+	if c.Report && c.Apply {
+		return c.apply(cli)
+	}
+	if c.Report {
+		return c.report(cli, l)
+	}
+	if c.Apply {
+		return errors.New("--apply requires --report")
+	}
+
+	client, err := adminClient(cli)
+	if err != nil {
+		return err
+	}
+
+	if c.Override {
+		if c.Instance == "" {
+			return errors.New("--override requires --instance")
+		}
+		var st gc.Status
+		if err := client.Post("/rest/gc/override", url.Values{"instance": {c.Instance}}, &st); err != nil {
+			return err
+		}
+		fmt.Println("Override accepted; garbage collection run started for", st.Instance)
+		return nil
+	}
+
+	if c.RunNow {
+		if c.Instance == "" {
+			var statuses []gc.Status
+			if err := client.Post("/rest/gc/run", nil, &statuses); err != nil {
+				return err
+			}
+			fmt.Println("Garbage collection run started for", len(statuses), "instances")
+			return nil
+		}
+		var st gc.Status
+		if err := client.Post("/rest/gc/run", url.Values{"instance": {c.Instance}}, &st); err != nil {
+			return err
+		}
+		fmt.Println("Garbage collection run started for", st.Instance)
+		return nil
+	}
+
+	var statuses []gc.Status
+	if err := client.Get("/rest/gc", nil, &statuses); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INSTANCE\tLAST RUN\tNEXT RUN\tREMOVED\tBLOCKED\tERROR")
+	for _, st := range statuses {
+		if c.Instance != "" && st.Instance != c.Instance {
+			continue
+		}
+		lastRun := "never"
+		if !st.LastRun.IsZero() {
+			lastRun = st.LastRun.Format(time.DateTime)
+		}
+		nextRun := "-"
+		if !st.NextRun.IsZero() {
+			nextRun = st.NextRun.Format(time.DateTime)
+		}
+		removed := "-"
+		if st.Report != nil {
+			removed = fmt.Sprintf("%d devices, %d folders, %d shares", st.Report.Devices, st.Report.Folders, st.Report.Shares)
+		}
+		blocked := "no"
+		if st.Blocked != nil {
+			devices, folders, shares := st.Blocked.Removals()
+			blocked = fmt.Sprintf("yes (%d devices, %d folders, %d shares)", devices, folders, shares)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", st.Instance, lastRun, nextRun, removed, blocked, st.LastError)
+	}
+	return tw.Flush()
+}
+
+// report connects directly to a Syncthing instance, without going via a
+// running daemon, and shows the removal plan. The state file is only read.
+func (c gcCmd) report(cli *CLI, l *slog.Logger) error {
+	if c.Instance == "" {
+		return errors.New("--report requires --instance")
+	}
+	cfg, err := loadConfig(cli.Config)
+	if err != nil {
+		return err
+	}
+	store, err := state.Open(cfg.StateFile)
+	if err != nil {
+		return err
+	}
+
+	// Find the instance by its address, which may come from Syncthing's
+	// own config
+	var syncthing *api.API
+	for _, inst := range cfg.Syncthing {
+		a, err := api.NewAPI(l, inst)
+		if err != nil {
+			return err
+		}
+		if a.Address() == c.Instance {
+			if !inst.Matches(cfg.GetGarbageCollect().GetInstanceSelector()) {
+				return fmt.Errorf("instance %s is not selected for garbage collection", c.Instance)
+			}
+			syncthing = a
+			break
+		}
+	}
+	if syncthing == nil {
+		return fmt.Errorf("instance %s not found in configuration", c.Instance)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = syncthing.Serve(ctx) }()
+
+	collector := gc.NewGarbageCollector(l, syncthing, cfg, nil, store)
+	plan, err := collector.Plan()
+	if err != nil {
+		return err
+	}
+
+	if err := c.printPlan(plan); err != nil {
+		return err
+	}
+	if limitErr := plan.CheckLimits(cfg.GarbageCollect); limitErr != nil {
+		fmt.Fprintln(os.Stderr, "Warning: safety limit exceeded:", limitErr)
+	}
+	return nil
+}
+
+// apply has the running daemon work out and perform the removal plan, as
+// the daemon owns the state file and would overwrite changes made to it
+// here.
+func (c gcCmd) apply(cli *CLI) error {
+	if c.Instance == "" {
+		return errors.New("--report requires --instance")
+	}
+	client, err := adminClient(cli)
+	if err != nil {
+		return fmt.Errorf("--apply requires a running daemon: %w", err)
+	}
+	query := url.Values{"instance": {c.Instance}}
+	if c.Override {
+		query.Set("override", "true")
+	}
+	var res gc.Applied
+	if err := client.Post("/rest/gc/apply", query, &res); err != nil {
+		return err
+	}
+	if err := c.printPlan(res.Plan); err != nil {
+		return err
+	}
+	rep := res.Report
+	fmt.Fprintf(os.Stderr, "Removed %d devices, %d folders and %d shares; %d no longer candidates, %d failed\n", rep.Devices, rep.Folders, rep.Shares, rep.Skipped, rep.Failed)
+	return nil
+}
+
+func (c gcCmd) printPlan(plan *gc.Plan) error {
+	if c.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+	return printPlan(plan)
+}
+
+func printPlan(plan *gc.Plan) error {
+	tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TYPE\tID\tNAME/PATH\tLAST SEEN\tDAYS\tPATTERN\tREASON\tPROTECTED")
+	now := time.Now()
+	for _, cands := range [][]gc.Candidate{plan.Devices, plan.Folders, plan.Shares} {
+		for _, cand := range cands {
+			id := cand.ID
+			if cand.Device != "" {
+				id = fmt.Sprintf("%s (%s)", cand.ID, cand.Device[:7])
+			}
+			name := cand.Name
+			if cand.Path != "" {
+				name = cand.Path
+			}
+			lastSeen, days := "-", "-"
+			if !cand.LastSeen.IsZero() {
+				lastSeen = cand.LastSeen.Format(time.DateTime)
+				days = strconv.Itoa(int(now.Sub(cand.LastSeen).Hours() / 24))
+			}
+			protected := "no"
+			if cand.Protected {
+				protected = "yes"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", cand.Type, id, name, lastSeen, days, cand.Pattern, cand.Reason, protected)
+		}
+	}
+	devices, folders, shares := plan.Removals()
+	fmt.Fprintf(tw, "\n%d of %d devices, %d of %d folders and %d of %d shares would be removed\n", devices, plan.TotalDevices, folders, plan.TotalFolders, shares, plan.TotalShares)
+	return tw.Flush()
+}
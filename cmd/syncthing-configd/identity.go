@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"text/tabwriter"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/events"
+)
+
+type identityCmd struct {
+	Accept string `help:"Accept the new identity of the given instance, as given in the configuration" placeholder:"INSTANCE"`
+	JSON   bool   `help:"Print the result as JSON"`
+}
+
+func (c identityCmd) Run(cli *CLI) error {
+	client, err := adminClient(cli)
+	if err != nil {
+		return err
+	}
+
+	if c.Accept != "" {
+		var id events.Identity
+		if err := client.Post("/rest/identity/accept", url.Values{"instance": {c.Accept}}, &id); err != nil {
+			return err
+		}
+		fmt.Println("Accepted identity", id.ID, "for", id.Instance)
+		return nil
+	}
+
+	var ids []events.Identity
+	if err := client.Get("/rest/identity", nil, &ids); err != nil {
+		return err
+	}
+
+	if c.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(ids)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INSTANCE\tDEVICE\tCHANGED TO")
+	for _, id := range ids {
+		device, mismatch := "-", "-"
+		if id.ID != protocol.EmptyDeviceID {
+			device = id.ID.String()
+		}
+		if id.Mismatch != protocol.EmptyDeviceID {
+			mismatch = id.Mismatch.String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", id.Instance, device, mismatch)
+	}
+	return tw.Flush()
+}
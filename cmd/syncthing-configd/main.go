@@ -6,6 +6,7 @@ import (
 	"log/slog"
 	"os"
 	"runtime"
+	_ "time/tzdata" // for garbage collection schedules in any time zone
 
 	"github.com/alecthomas/kong"
 	"github.com/lmittmann/tint"
@@ -13,22 +14,35 @@ import (
 	stevents "github.com/syncthing/syncthing/lib/events"
 	"github.com/thejerf/suture/v4"
 	"google.golang.org/protobuf/encoding/prototext"
+	"kastelo.dev/syncthing-configd/internal/admin"
 	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/bandwidth"
 	"kastelo.dev/syncthing-configd/internal/build"
 	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/discover"
 	"kastelo.dev/syncthing-configd/internal/events"
+	"kastelo.dev/syncthing-configd/internal/expiry"
 	"kastelo.dev/syncthing-configd/internal/gc"
+	"kastelo.dev/syncthing-configd/internal/hooks"
+	"kastelo.dev/syncthing-configd/internal/reconcile"
+	"kastelo.dev/syncthing-configd/internal/state"
 )
 
 type CLI struct {
 	Config string `short:"c" type:"existingfile" help:"Path to configd.conf" env:"CONFIG_FILE" default:"/etc/syncthing-configd/configd.conf"`
 	Debug  bool   `short:"d" help:"Enable debug logging" env:"DEBUG"`
+
+	Serve    serveCmd    `cmd:"" default:"1" help:"Run the configuration daemon (default)"`
+	Managed  managedCmd  `cmd:"" help:"List the devices and folders managed by a running daemon"`
+	GC       gcCmd       `cmd:"" name:"gc" help:"Show or control garbage collection in a running daemon"`
+	Expiry   expiryCmd   `cmd:"" help:"List or extend the expiry of devices in a running daemon"`
+	Identity identityCmd `cmd:"" help:"List or accept changed Syncthing instance identities in a running daemon"`
 }
 
 func main() {
 	// Parse CLI options
 	var cli CLI
-	kong.Parse(&cli)
+	ctx := kong.Parse(&cli)
 
 	// Created a logger with console coloring and optional debug logging
 	w := os.Stdout
@@ -44,6 +58,12 @@ func main() {
 	}))
 	slog.SetDefault(l)
 
+	ctx.FatalIfErrorf(ctx.Run(&cli, l))
+}
+
+type serveCmd struct{}
+
+func (serveCmd) Run(cli *CLI, l *slog.Logger) error {
 	l.Info("Starting Syncthing Configuration Daemon", "version", build.GitVersion, "os", runtime.GOOS, "arch", runtime.GOARCH)
 
 	config, err := loadConfig(cli.Config)
@@ -52,6 +72,15 @@ func main() {
 		os.Exit(1)
 	}
 
+	state, err := state.Open(config.StateFile)
+	if err != nil {
+		l.Error("Failed to load state", "error", err)
+		os.Exit(1)
+	}
+	if config.StateFile == "" && config.GetGarbageCollect().GetOnlyManaged() {
+		l.Warn("Garbage collection of only managed objects is enabled, but there is no state_file; nothing will be collected after a restart")
+	}
+
 	main := suture.New("main", suture.Spec{
 		FailureThreshold: 1,
 		EventHook: func(e suture.Event) {
@@ -64,24 +93,124 @@ func main() {
 		},
 	})
 
-	types := []stevents.EventType{stevents.ConfigSaved, stevents.DeviceRejected, stevents.FolderRejected}
-	for _, s := range config.Syncthing {
-		api := api.NewAPI(l, s.Address, s.ApiKey)
-		main.Add(api)
-
-		el := events.NewEventListener(l, api, config, types)
-		main.Add(el)
+	hooks := hooks.NewRunner(l, config.Hooks)
+
+	collectors := gc.NewCollectors()
+	peers := events.NewPeers()
+	drift := events.NewDriftCheckers()
+	if addr := config.GetAdmin().Address(); addr != "" {
+		srv := admin.NewServer(l, addr, config.Admin.GetApiKey(), state)
+		collectors.RegisterAdmin(srv)
+		peers.RegisterAdmin(srv)
+		drift.RegisterAdmin(srv)
+		expiry.RegisterAdmin(srv, state)
+		main.Add(srv)
+	}
 
-		if config.GetGarbageCollect().GetRunEveryS() > 0 {
-			gc := gc.NewGarbageCollector(l, api, config.GarbageCollect)
-			main.Add(gc)
+	starter := &instanceStarter{
+		log:        l,
+		cfg:        config,
+		hooks:      hooks,
+		state:      state,
+		collectors: collectors,
+		peers:      peers,
+		drift:      drift,
+	}
+	for _, s := range config.Syncthing {
+		if _, err := starter.start(main, s); err != nil {
+			l.Error("Failed to set up Syncthing API", "instance", s.DisplayName(), "error", err)
+			os.Exit(1)
+		}
+	}
+	for _, d := range config.Discover {
+		disc, err := discover.New(l, d, main, starter.start)
+		if err != nil {
+			l.Error("Failed to set up discovery", "error", err)
+			os.Exit(1)
 		}
+		main.Add(disc)
 	}
 
+	// Run garbage collection on all instances on SIGUSR1
+	sigs := make(chan os.Signal, 1)
+	notifyRunNow(sigs)
+	go func() {
+		for range sigs {
+			l.Info("Received signal, starting garbage collection")
+			collectors.RunAll()
+		}
+	}()
+
 	if err := main.Serve(context.Background()); err != nil {
 		l.Error("Failed to run service", "error", err)
 		os.Exit(1)
 	}
+	return nil
+}
+
+// instanceStarter sets up the services for each Syncthing instance.
+type instanceStarter struct {
+	log        *slog.Logger
+	cfg        *config.Configuration
+	hooks      *hooks.Runner
+	state      *state.Store
+	collectors *gc.Collectors
+	peers      *events.Peers
+	drift      *events.DriftCheckers
+}
+
+var eventTypes = []stevents.EventType{stevents.ConfigSaved, stevents.DeviceRejected, stevents.FolderRejected}
+
+// start adds the services for the instance to the supervisor.
+func (s *instanceStarter) start(sup *suture.Supervisor, inst *config.SyncthingInstance) (func(), error) {
+	api, err := api.NewAPI(s.log, inst)
+	if err != nil {
+		return nil, err
+	}
+	sup.Add(api)
+
+	el := events.NewEventListener(s.log, api, s.cfg, eventTypes, s.hooks, s.state, inst, s.peers)
+	s.peers.Add(el)
+	sup.Add(el)
+	stops := []func(){func() { s.peers.Remove(el) }}
+
+	for _, pat := range s.cfg.Pattern {
+		if len(pat.BandwidthSchedule) > 0 && inst.Matches(pat.InstanceSelector) {
+			sup.Add(bandwidth.New(s.log, api, s.cfg, s.state, inst))
+			break
+		}
+	}
+	for _, pat := range s.cfg.Pattern {
+		if pat.TTL() > 0 {
+			sup.Add(expiry.New(s.log, api, s.cfg, s.state))
+			break
+		}
+	}
+
+	if len(s.cfg.Device)+len(s.cfg.Folder) > 0 || s.cfg.Options != nil {
+		sup.Add(reconcile.New(s.log, api, s.cfg, inst))
+	}
+
+	if len(s.cfg.Pattern) > 0 && s.cfg.DriftCheckIntervalS != -1 {
+		dc := events.NewDriftChecker(el)
+		s.drift.Add(dc)
+		sup.Add(dc)
+		stops = append(stops, func() { s.drift.Remove(dc) })
+	}
+
+	// Without a schedule, the collector only runs on request
+	if gcCfg := s.cfg.GetGarbageCollect(); gcCfg != nil && inst.Matches(gcCfg.GetInstanceSelector()) {
+		gc := gc.NewGarbageCollector(s.log, api, s.cfg, s.hooks, s.state)
+		s.collectors.Add(gc)
+		sup.Add(gc)
+		stops = append(stops, func() { s.collectors.Remove(gc) })
+	}
+
+	return func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}, nil
 }
 
 func loadConfig(path string) (*config.Configuration, error) {
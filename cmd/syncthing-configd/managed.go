@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/admin"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+type managedCmd struct {
+	JSON bool `help:"Print the result as JSON"`
+}
+
+func (c managedCmd) Run(cli *CLI) error {
+	client, err := adminClient(cli)
+	if err != nil {
+		return err
+	}
+
+	var instances map[protocol.DeviceID]state.Instance
+	if err := client.Get("/rest/managed", nil, &instances); err != nil {
+		return err
+	}
+
+	if c.JSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(instances)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INSTANCE\tTYPE\tID\tNAME/PATH\tPATTERN\tCREATED")
+	for _, instID := range sortedKeys(instances, protocol.DeviceID.String) {
+		inst := instances[instID]
+		for _, id := range sortedKeys(inst.Devices, protocol.DeviceID.String) {
+			dev := inst.Devices[id]
+			fmt.Fprintf(tw, "%s\tdevice\t%s\t%s\t%s\t%s\n", instID.Short(), id, dev.Name, dev.Pattern, dev.Accepted.Format(time.DateTime))
+		}
+		for _, id := range sortedKeys(inst.Folders, func(s string) string { return s }) {
+			fld := inst.Folders[id]
+			fmt.Fprintf(tw, "%s\tfolder\t%s\t%s\t%s\t%s\n", instID.Short(), id, fld.Path, fld.Pattern, fld.Created.Format(time.DateTime))
+		}
+	}
+	return tw.Flush()
+}
+
+// adminClient returns a client for the admin API of the daemon using the
+// same configuration file.
+func adminClient(cli *CLI) (*admin.Client, error) {
+	cfg, err := loadConfig(cli.Config)
+	if err != nil {
+		return nil, err
+	}
+	addr := cfg.GetAdmin().Address()
+	if addr == "" {
+		return nil, errors.New("the admin API is not enabled in the configuration")
+	}
+	return admin.NewClient(addr, cfg.Admin.GetApiKey())
+}
+
+func sortedKeys[K comparable, V any](m map[K]V, str func(K) string) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(a, b int) bool {
+		return str(keys[a]) < str(keys[b])
+	})
+	return keys
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func notifyRunNow(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGUSR1)
+}
@@ -0,0 +1,6 @@
+package main
+
+import "os"
+
+// There is no SIGUSR1 on Windows; use the admin API instead.
+func notifyRunNow(chan<- os.Signal) {}
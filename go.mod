@@ -9,6 +9,7 @@ require (
 	github.com/go-resty/resty/v2 v2.14.0
 	github.com/lmittmann/tint v1.0.5
 	github.com/mattn/go-isatty v0.0.20
+	github.com/prometheus/client_golang v1.19.1
 	github.com/syncthing/syncthing v1.27.10
 	github.com/thejerf/suture/v4 v4.0.5
 	google.golang.org/protobuf v1.34.2
@@ -29,7 +30,6 @@ require (
 	github.com/onsi/gomega v1.30.0 // indirect
 	github.com/pierrec/lz4/v4 v4.1.21 // indirect
 	github.com/power-devops/perfstat v0.0.0-20240221224432-82ca36839d55 // indirect
-	github.com/prometheus/client_golang v1.19.1 // indirect
 	github.com/prometheus/client_model v0.6.1 // indirect
 	github.com/prometheus/common v0.55.0 // indirect
 	github.com/prometheus/procfs v0.15.1 // indirect
@@ -0,0 +1,131 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+// Server is the admin API, a small HTTP server exposing the state of
+// configd and accepting administrative commands.
+type Server struct {
+	log     *slog.Logger
+	address string
+	apiKey  string
+	mux     *http.ServeMux
+}
+
+// NewServer returns an admin API server. With an API key, every request
+// must carry it as a bearer token.
+func NewServer(log *slog.Logger, address, apiKey string, store *state.Store) *Server {
+	s := &Server{
+		log:     log,
+		address: address,
+		apiKey:  apiKey,
+		mux:     http.NewServeMux(),
+	}
+	s.Handle(http.MethodGet, "/rest/managed", func(_ *http.Request) (any, error) {
+		return store.Instances(), nil
+	})
+	s.mux.Handle("/metrics", promhttp.Handler())
+	return s
+}
+
+// Handle registers a handler for the given method and path. The value
+// returned by the handler is sent to the client as JSON; an error results
+// in a 500 response, unless it's an *Error with a specific status code.
+func (s *Server) Handle(method, path string, fn func(*http.Request) (any, error)) {
+	s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != method {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		res, err := fn(r)
+		if err != nil {
+			var aerr *Error
+			if errors.As(err, &aerr) {
+				http.Error(w, aerr.Message, aerr.Status)
+				return
+			}
+			s.log.Error("Admin request failed", "path", path, "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(res)
+	})
+}
+
+func (s *Server) Serve(ctx context.Context) error {
+	l, err := net.Listen("tcp", s.address)
+	if err != nil {
+		s.log.Error("Failed to listen", "error", err)
+		return err
+	}
+
+	srv := &http.Server{
+		Handler:           s.authenticated(s.mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+
+	s.log.Info("Admin API listening", "address", l.Addr())
+	if err := srv.Serve(l); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return ctx.Err()
+}
+
+func (s *Server) authenticated(next http.Handler) http.Handler {
+	if s.apiKey == "" {
+		return next
+	}
+	want := []byte("Bearer " + s.apiKey)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) String() string {
+	return fmt.Sprintf("admin(%s)@%p", s.address, s)
+}
+
+// Error is an error with an associated HTTP status code.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func BadRequest(format string, args ...any) error {
+	return &Error{Status: http.StatusBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+func NotFound(format string, args ...any) error {
+	return &Error{Status: http.StatusNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+func Conflict(format string, args ...any) error {
+	return &Error{Status: http.StatusConflict, Message: fmt.Sprintf(format, args...)}
+}
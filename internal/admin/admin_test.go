@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthentication(t *testing.T) {
+	t.Parallel()
+
+	s := NewServer(slog.Default(), "", "secret", nil)
+	s.Handle(http.MethodGet, "/rest/ping", func(_ *http.Request) (any, error) {
+		return "pong", nil
+	})
+	srv := httptest.NewServer(s.authenticated(s.mux))
+	defer srv.Close()
+	addr := strings.TrimPrefix(srv.URL, "http://")
+
+	for _, key := range []string{"", "wrong"} {
+		c, err := NewClient(addr, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Get("/rest/ping", nil, nil); err == nil {
+			t.Errorf("request with key %q succeeded", key)
+		}
+	}
+
+	c, err := NewClient(addr, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var res string
+	if err := c.Get("/rest/ping", nil, &res); err != nil || res != "pong" {
+		t.Errorf("got %q, %v", res, err)
+	}
+}
@@ -0,0 +1,80 @@
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client talks to the admin API of a running configd.
+type Client struct {
+	base   string
+	apiKey string
+	client *http.Client
+}
+
+// NewClient returns a client for the admin API listening on the given
+// address, as given in the configuration. An unspecified host means
+// localhost. The API key, if any, is sent with every request.
+func NewClient(address, apiKey string) (*Client, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("admin address: %w", err)
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return &Client{
+		base:   "http://" + net.JoinHostPort(host, port),
+		apiKey: apiKey,
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// Get performs a GET request and decodes the JSON response into res.
+func (c *Client) Get(path string, query url.Values, res any) error {
+	return c.do(http.MethodGet, path, query, res)
+}
+
+// Post performs a POST request and decodes the JSON response into res,
+// which may be nil.
+func (c *Client) Post(path string, query url.Values, res any) error {
+	return c.do(http.MethodPost, path, query, res)
+}
+
+func (c *Client) do(method, path string, query url.Values, res any) error {
+	u := c.base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequest(method, u, nil)
+	if err != nil {
+		return err
+	}
+	if c.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		bs, _ := io.ReadAll(resp.Body)
+		msg := strings.TrimSpace(string(bs))
+		if msg == "" {
+			msg = resp.Status
+		}
+		return errors.New(msg)
+	}
+	if res == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(res)
+}
@@ -2,10 +2,14 @@ package api
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -13,8 +17,13 @@ import (
 	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/thejerf/suture/v4"
+	"kastelo.dev/syncthing-configd/internal/config"
 )
 
+// unixPrefix marks an address as the path of a Unix domain socket, as in
+// Syncthing's own GUI address setting.
+const unixPrefix = "unix://"
+
 type API struct {
 	suture.Service
 	log             *slog.Logger
@@ -23,23 +32,57 @@ type API struct {
 	serialisedFuncs chan func()
 	configChangers  chan func(cfg *stconfig.Configuration)
 	events          chan events.Event
+
+	identityMut sync.Mutex
+	identity    protocol.DeviceID // first seen, or accepted
+	mismatch    protocol.DeviceID // seen since, awaiting acceptance
 }
 
-func NewAPI(l *slog.Logger, address string, apiKey string) *API {
-	url := fmt.Sprintf("http://%s/rest/", address)
+func NewAPI(l *slog.Logger, inst *config.SyncthingInstance) (*API, error) {
+	inst, err := resolveInstance(inst)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := newAPIKeySource(inst)
+	if err != nil {
+		return nil, err
+	}
+	tlsCfg, err := tlsConfig(inst)
+	if err != nil {
+		return nil, err
+	}
 
 	c := resty.New()
+	url := fmt.Sprintf("%s://%s/rest/", inst.URLScheme(), inst.Address)
+	if path, ok := strings.CutPrefix(inst.Address, unixPrefix); ok {
+		// The host part of the URL is irrelevant, as every connection
+		// goes to the socket.
+		url = fmt.Sprintf("%s://localhost/rest/", inst.URLScheme())
+		c.SetTransport(&http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		})
+	}
 	c.SetBaseURL(url)
 	c.SetAuthScheme("Bearer")
-	c.SetAuthToken(apiKey)
-	c.SetTLSClientConfig(&tls.Config{InsecureSkipVerify: true})
+	c.OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+		key, err := keys.Key()
+		if err != nil {
+			return err
+		}
+		r.SetAuthToken(key)
+		return nil
+	})
+	c.SetTLSClientConfig(tlsCfg)
 
 	svc := suture.NewSimple("API")
 
 	api := &API{
 		Service:         svc,
-		log:             l.With("address", address),
-		address:         address,
+		log:             l.With("address", inst.Address),
+		address:         inst.Address,
 		client:          c,
 		serialisedFuncs: make(chan func(), 1),
 		configChangers:  make(chan func(cfg *stconfig.Configuration), 1),
@@ -48,13 +91,87 @@ func NewAPI(l *slog.Logger, address string, apiKey string) *API {
 
 	svc.Add(configService{API: api})
 
-	return api
+	return api, nil
 }
 
 func (s *API) Address() string {
 	return s.address
 }
 
+// ErrIdentityMismatch is returned when the Syncthing instance behind the
+// address is no longer the one first seen there.
+var ErrIdentityMismatch = errors.New("instance identity changed")
+
+// SetIdentity records the device ID of the Syncthing instance, as seen by
+// the event listener. The first identity seen is kept; a different one is
+// recorded as a mismatch, and an error returned, until it's accepted with
+// AcceptIdentity or the original instance is back.
+func (s *API) SetIdentity(id protocol.DeviceID) error {
+	s.identityMut.Lock()
+	defer s.identityMut.Unlock()
+	if s.identity == protocol.EmptyDeviceID || s.identity == id {
+		s.identity = id
+		s.mismatch = protocol.EmptyDeviceID
+		return nil
+	}
+	s.mismatch = id
+	return fmt.Errorf("%w from %s to %s", ErrIdentityMismatch, s.identity.Short(), id.Short())
+}
+
+// Identity returns the recorded device ID of the Syncthing instance, or
+// the empty device ID if it's not yet known or has changed without being
+// accepted.
+func (s *API) Identity() protocol.DeviceID {
+	s.identityMut.Lock()
+	defer s.identityMut.Unlock()
+	if s.mismatch != protocol.EmptyDeviceID {
+		return protocol.EmptyDeviceID
+	}
+	return s.identity
+}
+
+// VerifyIdentity returns an error if the device ID differs from the
+// recorded one, or if a changed identity is awaiting acceptance. If no
+// identity is recorded yet, the device ID becomes the recorded one.
+func (s *API) VerifyIdentity(id protocol.DeviceID) error {
+	s.identityMut.Lock()
+	defer s.identityMut.Unlock()
+	if s.mismatch != protocol.EmptyDeviceID {
+		return fmt.Errorf("%w from %s to %s; not accepted yet", ErrIdentityMismatch, s.identity.Short(), s.mismatch.Short())
+	}
+	if s.identity == protocol.EmptyDeviceID {
+		s.identity = id
+		return nil
+	}
+	if s.identity != id {
+		s.mismatch = id
+		return fmt.Errorf("%w from %s to %s", ErrIdentityMismatch, s.identity.Short(), id.Short())
+	}
+	return nil
+}
+
+// IdentityStatus returns the recorded identity and the mismatching one
+// awaiting acceptance, if any.
+func (s *API) IdentityStatus() (recorded, mismatch protocol.DeviceID) {
+	s.identityMut.Lock()
+	defer s.identityMut.Unlock()
+	return s.identity, s.mismatch
+}
+
+// AcceptIdentity makes the mismatching identity the recorded one, so that
+// configd operates on the instance again. It returns false if there is no
+// mismatch.
+func (s *API) AcceptIdentity() bool {
+	s.identityMut.Lock()
+	defer s.identityMut.Unlock()
+	if s.mismatch == protocol.EmptyDeviceID {
+		return false
+	}
+	s.identity = s.mismatch
+	s.mismatch = protocol.EmptyDeviceID
+	return true
+}
+
 func (s *API) String() string {
 	return fmt.Sprintf("api(%s)@%p", s.address, s)
 }
@@ -79,6 +196,7 @@ func (s configService) Serve(ctx context.Context) error {
 			cfg, err := s.GetConfig()
 			if err != nil {
 				s.log.Error("Failed to get config", "error", err)
+				fn(nil) // let the caller fail instead of waiting forever
 				continue
 			}
 			fn(cfg)
@@ -102,13 +220,121 @@ func (s *API) GetConfig() (*stconfig.Configuration, error) {
 	var cfg stconfig.Configuration
 	r := s.client.R()
 	r.SetResult(&cfg)
-	_, err := r.Get("config")
+	resp, err := r.Get("config")
 	if err != nil {
 		return nil, err
 	}
+	if resp.IsError() {
+		return nil, errors.New(resp.Status())
+	}
 	return &cfg, nil
 }
 
+// ConfigTx gives access to Syncthing from within a configuration
+// transaction, where the serialised API methods would deadlock.
+type ConfigTx struct {
+	api    *API
+	Config *stconfig.Configuration
+}
+
+// InConfigTx calls fn with the current configuration, serialised with all
+// other configuration changes.
+func (s *API) InConfigTx(fn func(tx *ConfigTx) error) error {
+	errC := make(chan error, 1)
+	s.configChangers <- func(cur *stconfig.Configuration) {
+		if cur == nil {
+			errC <- errors.New("getting config failed")
+			return
+		}
+		errC <- fn(&ConfigTx{api: s, Config: cur})
+	}
+	return <-errC
+}
+
+func (t *ConfigTx) GetSystemStatus() (*SystemStatus, error) {
+	return t.api.getSystemStatus()
+}
+
+func (t *ConfigTx) GetDeviceStats() (map[protocol.DeviceID]DeviceStatistics, error) {
+	return t.api.getDeviceStats()
+}
+
+// PutDevice creates or replaces the device configuration.
+func (t *ConfigTx) PutDevice(cfg stconfig.DeviceConfiguration) error {
+	return t.api.put("config/devices/"+cfg.DeviceID.String(), cfg)
+}
+
+// PutFolder creates or replaces the folder configuration.
+func (t *ConfigTx) PutFolder(cfg stconfig.FolderConfiguration) error {
+	return t.api.put("config/folders/"+cfg.ID, cfg)
+}
+
+// RemoveDevice removes the device, which also stops sharing any folders
+// with it.
+func (t *ConfigTx) RemoveDevice(id protocol.DeviceID) error {
+	return t.api.delete("config/devices/" + id.String())
+}
+
+// RemoveFolder removes the folder.
+func (t *ConfigTx) RemoveFolder(id string) error {
+	return t.api.delete("config/folders/" + id)
+}
+
+// RemoveFolderDevice stops sharing the folder with the given device, while
+// keeping both the folder and the device.
+func (t *ConfigTx) RemoveFolderDevice(folderID string, deviceID protocol.DeviceID) error {
+	fld, _, ok := t.Config.Folder(folderID)
+	if !ok {
+		return fmt.Errorf("folder %s not found", folderID)
+	}
+	devices := make([]stconfig.FolderDeviceConfiguration, 0, len(fld.Devices))
+	for _, d := range fld.Devices {
+		if d.DeviceID != deviceID {
+			devices = append(devices, d)
+		}
+	}
+	fld.Devices = devices
+	return t.api.put("config/folders/"+fld.ID, fld)
+}
+
+// PatchDevice changes the given fields, by JSON name, of an existing
+// device.
+func (t *ConfigTx) PatchDevice(id protocol.DeviceID, fields map[string]any) error {
+	r := t.api.client.R()
+	r.SetBody(fields)
+	resp, err := r.Patch("config/devices/" + id.String())
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return errors.New(resp.Status())
+	}
+	return nil
+}
+
+// PutOptions replaces the global options.
+func (t *ConfigTx) PutOptions(cfg stconfig.OptionsConfiguration) error {
+	return t.api.put("config/options", cfg)
+}
+
+// PutGUI replaces the GUI settings.
+func (t *ConfigTx) PutGUI(cfg stconfig.GUIConfiguration) error {
+	return t.api.put("config/gui", cfg)
+}
+
+func (s *API) put(path string, body any) error {
+	r := s.client.R()
+	r.SetBody(body)
+	resp, err := r.Put(path)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return errors.New(resp.Status())
+	}
+	return nil
+}
+
 type SystemStatus struct {
 	MyID protocol.DeviceID `json:"myID"`
 }
@@ -126,24 +352,26 @@ func maybeFunc[T any](f func() (T, error)) maybe[T] {
 func (s *API) GetSystemStatus() (*SystemStatus, error) {
 	resC := make(chan maybe[*SystemStatus], 1)
 	s.serialisedFuncs <- func() {
-		resC <- maybeFunc(func() (*SystemStatus, error) {
-			var status SystemStatus
-			r := s.client.R()
-			r.SetResult(&status)
-			resp, err := r.Get("system/status")
-			if err != nil {
-				return nil, err
-			}
-			if resp.IsError() {
-				return nil, errors.New(resp.Status())
-			}
-			return &status, nil
-		})
+		resC <- maybeFunc(s.getSystemStatus)
 	}
 	res := <-resC
 	return res.value, res.err
 }
 
+func (s *API) getSystemStatus() (*SystemStatus, error) {
+	var status SystemStatus
+	r := s.client.R()
+	r.SetResult(&status)
+	resp, err := r.Get("system/status")
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, errors.New(resp.Status())
+	}
+	return &status, nil
+}
+
 type SystemVersion struct {
 	Version string
 	OS      string
@@ -179,129 +407,273 @@ type DeviceStatistics struct {
 func (s *API) GetDeviceStats() (map[protocol.DeviceID]DeviceStatistics, error) {
 	resC := make(chan maybe[map[protocol.DeviceID]DeviceStatistics], 1)
 	s.serialisedFuncs <- func() {
-		resC <- maybeFunc(func() (map[protocol.DeviceID]DeviceStatistics, error) {
-			res := make(map[protocol.DeviceID]DeviceStatistics)
-			r := s.client.R()
-			r.SetResult(&res)
-			resp, err := r.Get("stats/device")
-			if err != nil {
-				return nil, err
-			}
-			if resp.IsError() {
-				return nil, errors.New(resp.Status())
-			}
-			return res, nil
+		resC <- maybeFunc(s.getDeviceStats)
+	}
+	res := <-resC
+	return res.value, res.err
+}
+
+func (s *API) getDeviceStats() (map[protocol.DeviceID]DeviceStatistics, error) {
+	res := make(map[protocol.DeviceID]DeviceStatistics)
+	r := s.client.R()
+	r.SetResult(&res)
+	resp, err := r.Get("stats/device")
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, errors.New(resp.Status())
+	}
+	return res, nil
+}
+
+type FolderStatistics struct {
+	LastFile struct {
+		At       time.Time
+		Filename string
+		Deleted  bool
+	} // the last file received from a remote device
+}
+
+func (s *API) GetFolderStats() (map[string]FolderStatistics, error) {
+	resC := make(chan maybe[map[string]FolderStatistics], 1)
+	s.serialisedFuncs <- func() {
+		resC <- maybeFunc(s.getFolderStats)
+	}
+	res := <-resC
+	return res.value, res.err
+}
+
+func (s *API) getFolderStats() (map[string]FolderStatistics, error) {
+	res := make(map[string]FolderStatistics)
+	r := s.client.R()
+	r.SetResult(&res)
+	resp, err := r.Get("stats/folder")
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, errors.New(resp.Status())
+	}
+	return res, nil
+}
+
+// RemoteStateValid is the remote state of a folder that the remote device
+// is currently sharing with us.
+const RemoteStateValid = "valid"
+
+type FolderCompletion struct {
+	Completion  float64
+	GlobalBytes int64
+	NeedBytes   int64
+	RemoteState string // "valid", "paused", "notSharing" or "unknown"
+}
+
+func (s *API) GetCompletion(folderID string, deviceID protocol.DeviceID) (*FolderCompletion, error) {
+	resC := make(chan maybe[*FolderCompletion], 1)
+	s.serialisedFuncs <- func() {
+		resC <- maybeFunc(func() (*FolderCompletion, error) {
+			return s.getCompletion(folderID, deviceID)
 		})
 	}
 	res := <-resC
 	return res.value, res.err
 }
 
-func (s *API) SetDevice(cfg *stconfig.DeviceConfiguration) error {
-	errC := make(chan error, 1)
+func (s *API) getCompletion(folderID string, deviceID protocol.DeviceID) (*FolderCompletion, error) {
+	var comp FolderCompletion
+	r := s.client.R()
+	r.SetQueryParam("folder", folderID)
+	r.SetQueryParam("device", deviceID.String())
+	r.SetResult(&comp)
+	resp, err := r.Get("db/completion")
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, errors.New(resp.Status())
+	}
+	return &comp, nil
+}
+
+// SetDevice adds the device to the configuration, unless it already
+// exists. It returns true if the device was added.
+func (s *API) SetDevice(cfg *stconfig.DeviceConfiguration) (bool, error) {
+	resC := make(chan maybe[bool], 1)
 	s.configChangers <- func(cur *stconfig.Configuration) {
-		if cur == nil {
-			errC <- errors.New("getting config failed")
-			return
-		}
-		for _, d := range cur.Devices {
-			if d.DeviceID == cfg.DeviceID {
-				errC <- nil
-				return
+		resC <- maybeFunc(func() (bool, error) {
+			if cur == nil {
+				return false, errors.New("getting config failed")
+			}
+			for _, d := range cur.Devices {
+				if d.DeviceID == cfg.DeviceID {
+					return false, nil
+				}
 			}
-		}
 
-		r := s.client.R()
-		r.SetBody(cfg)
-		resp, err := r.Put("config/devices/" + cfg.DeviceID.String())
-		if err != nil {
-			errC <- err
-			return
-		}
-		if resp.IsError() {
-			errC <- errors.New(resp.Status())
-			return
-		}
-		errC <- nil
+			r := s.client.R()
+			r.SetBody(cfg)
+			resp, err := r.Put("config/devices/" + cfg.DeviceID.String())
+			if err != nil {
+				return false, err
+			}
+			if resp.IsError() {
+				return false, errors.New(resp.Status())
+			}
+			return true, nil
+		})
 	}
-	return <-errC
+	res := <-resC
+	return res.value, res.err
 }
 
-func (s *API) SetFolder(cfg *stconfig.FolderConfiguration) error {
-	errC := make(chan error, 1)
+// SetFolder adds the folder to the configuration, or adds the folder's
+// devices to the existing folder if it already exists. It returns true if
+// the folder was created.
+func (s *API) SetFolder(cfg *stconfig.FolderConfiguration) (bool, error) {
+	resC := make(chan maybe[bool], 1)
 	s.configChangers <- func(cur *stconfig.Configuration) {
-		if cur == nil {
-			errC <- errors.New("getting config failed")
-			return
-		}
+		resC <- maybeFunc(func() (bool, error) {
+			if cur == nil {
+				return false, errors.New("getting config failed")
+			}
 
-		var resp *resty.Response
-		var err error
-		exists := false
-		r := s.client.R()
-		for _, f := range cur.Folders {
-			if f.ID == cfg.ID {
-				// Folder exists, just add device
-				exists = true
-				f.Devices = append(f.Devices, cfg.Devices...)
-
-				r.SetBody(f)
-				resp, err = r.Patch("config/folders/" + f.ID)
-				break
+			var resp *resty.Response
+			var err error
+			exists := false
+			r := s.client.R()
+			for _, f := range cur.Folders {
+				if f.ID == cfg.ID {
+					// Folder exists, just add the devices it isn't
+					// already shared with
+					exists = true
+					n := len(f.Devices)
+					for _, dev := range cfg.Devices {
+						if !slices.ContainsFunc(f.Devices, func(d stconfig.FolderDeviceConfiguration) bool { return d.DeviceID == dev.DeviceID }) {
+							f.Devices = append(f.Devices, dev)
+						}
+					}
+					if len(f.Devices) == n {
+						return false, nil
+					}
+
+					r.SetBody(f)
+					resp, err = r.Patch("config/folders/" + f.ID)
+					break
+				}
 			}
-		}
 
-		if !exists {
-			// Folder does not exist, create it
-			r.SetBody(cfg)
-			resp, err = r.Post("config/folders")
-		}
+			if !exists {
+				// Folder does not exist, create it
+				r.SetBody(cfg)
+				resp, err = r.Post("config/folders")
+			}
 
-		if err != nil {
-			errC <- err
-			return
-		}
-		if resp.IsError() {
-			errC <- errors.New(resp.Status())
-			return
-		}
-		errC <- nil
+			if err != nil {
+				return false, err
+			}
+			if resp.IsError() {
+				return false, errors.New(resp.Status())
+			}
+			return !exists, nil
+		})
 	}
-	return <-errC
+	res := <-resC
+	return res.value, res.err
 }
 
 func (s *API) RemoveFolder(folderID string) error {
 	errC := make(chan error, 1)
-	s.configChangers <- func(cur *stconfig.Configuration) {
-		r := s.client.R()
-		resp, err := r.Delete("config/folders/" + folderID)
-		if err != nil {
-			errC <- err
-			return
-		}
-		if resp.IsError() {
-			errC <- errors.New(resp.Status())
-			return
-		}
-		errC <- nil
+	s.configChangers <- func(_ *stconfig.Configuration) {
+		errC <- s.delete("config/folders/" + folderID)
 	}
 	return <-errC
 }
 
 func (s *API) RemoveDevice(deviceID protocol.DeviceID) error {
 	errC := make(chan error, 1)
-	s.configChangers <- func(cur *stconfig.Configuration) {
-		r := s.client.R()
-		resp, err := r.Delete("config/devices/" + deviceID.String())
-		if err != nil {
-			errC <- err
-			return
-		}
-		if resp.IsError() {
-			errC <- errors.New(resp.Status())
-			return
-		}
-		errC <- nil
+	s.configChangers <- func(_ *stconfig.Configuration) {
+		errC <- s.delete("config/devices/" + deviceID.String())
 	}
 	return <-errC
 }
+
+func (s *API) delete(path string) error {
+	resp, err := s.client.R().Delete(path)
+	if err != nil {
+		return err
+	}
+	if resp.IsError() {
+		return errors.New(resp.Status())
+	}
+	return nil
+}
+
+// IgnoreDevice adds the device to the remote ignored devices, so that
+// Syncthing no longer asks about it. It returns true if the device wasn't
+// already ignored.
+func (s *API) IgnoreDevice(dev stconfig.ObservedDevice) (bool, error) {
+	resC := make(chan maybe[bool], 1)
+	s.configChangers <- func(cur *stconfig.Configuration) {
+		resC <- maybeFunc(func() (bool, error) {
+			if cur == nil {
+				return false, errors.New("getting config failed")
+			}
+			for _, d := range cur.IgnoredDevices {
+				if d.ID == dev.ID {
+					return false, nil
+				}
+			}
+			cur.IgnoredDevices = append(cur.IgnoredDevices, dev)
+
+			// There is no separate endpoint for the ignored devices
+			r := s.client.R()
+			r.SetBody(cur)
+			resp, err := r.Put("config")
+			if err != nil {
+				return false, err
+			}
+			if resp.IsError() {
+				return false, errors.New(resp.Status())
+			}
+			return true, nil
+		})
+	}
+	res := <-resC
+	return res.value, res.err
+}
+
+// UnignoreDevice removes the device from the remote ignored devices, so
+// that Syncthing asks about it again. It returns true if the device was
+// ignored.
+func (s *API) UnignoreDevice(id protocol.DeviceID) (bool, error) {
+	resC := make(chan maybe[bool], 1)
+	s.configChangers <- func(cur *stconfig.Configuration) {
+		resC <- maybeFunc(func() (bool, error) {
+			if cur == nil {
+				return false, errors.New("getting config failed")
+			}
+			idx := slices.IndexFunc(cur.IgnoredDevices, func(d stconfig.ObservedDevice) bool {
+				return d.ID == id
+			})
+			if idx < 0 {
+				return false, nil
+			}
+			cur.IgnoredDevices = slices.Delete(cur.IgnoredDevices, idx, idx+1)
+
+			// There is no separate endpoint for the ignored devices
+			r := s.client.R()
+			r.SetBody(cur)
+			resp, err := r.Put("config")
+			if err != nil {
+				return false, err
+			}
+			if resp.IsError() {
+				return false, errors.New(resp.Status())
+			}
+			return true, nil
+		})
+	}
+	res := <-resC
+	return res.value, res.err
+}
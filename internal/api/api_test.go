@@ -0,0 +1,99 @@
+package api
+
+import (
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+func TestUnixSocket(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "gui.sock")
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		t.Skip("no unix sockets:", err)
+	}
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/config" || r.Header.Get("Authorization") != "Bearer abc123" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"version": 37}`))
+	})}
+	go func() { _ = srv.Serve(l) }()
+	defer srv.Close()
+
+	api, err := NewAPI(slog.Default(), &config.SyncthingInstance{Address: "unix://" + path, ApiKey: "abc123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := api.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Version != 37 {
+		t.Errorf("got config version %d, want 37", cfg.Version)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	t.Parallel()
+
+	id1 := protocol.DeviceID{1}
+	id2 := protocol.DeviceID{2}
+	api := &API{}
+
+	// The first identity seen is kept
+	if err := api.VerifyIdentity(id1); err != nil {
+		t.Fatal(err)
+	}
+	if err := api.SetIdentity(id1); err != nil {
+		t.Fatal(err)
+	}
+	if api.Identity() != id1 {
+		t.Fatal("identity not recorded")
+	}
+
+	// A different one is refused until accepted
+	if err := api.SetIdentity(id2); !errors.Is(err, ErrIdentityMismatch) {
+		t.Fatal("expected mismatch, got", err)
+	}
+	if api.Identity() != protocol.EmptyDeviceID {
+		t.Error("identity should be unknown while mismatched")
+	}
+	if err := api.VerifyIdentity(id2); !errors.Is(err, ErrIdentityMismatch) {
+		t.Error("expected mismatch, got", err)
+	}
+	if rec, mismatch := api.IdentityStatus(); rec != id1 || mismatch != id2 {
+		t.Error("unexpected status", rec, mismatch)
+	}
+	if !api.AcceptIdentity() {
+		t.Fatal("expected a mismatch to accept")
+	}
+	if err := api.VerifyIdentity(id2); err != nil {
+		t.Error(err)
+	}
+	if api.AcceptIdentity() {
+		t.Error("nothing left to accept")
+	}
+
+	// The previous instance coming back is also a mismatch now, but goes
+	// away when the accepted instance is seen again
+	if err := api.VerifyIdentity(id1); !errors.Is(err, ErrIdentityMismatch) {
+		t.Error("expected mismatch, got", err)
+	}
+	if err := api.SetIdentity(id2); err != nil {
+		t.Error(err)
+	}
+	if api.Identity() != id2 {
+		t.Error("identity should be back")
+	}
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/pem"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"google.golang.org/protobuf/proto"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+// guiConfig is the part of Syncthing's config.xml that we care about.
+type guiConfig struct {
+	TLS     bool   `xml:"tls,attr"`
+	Address string `xml:"address"`
+	APIKey  string `xml:"apikey"`
+}
+
+func readGUIConfig(path string) (*guiConfig, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cfg struct {
+		GUI guiConfig `xml:"gui"`
+	}
+	if err := xml.Unmarshal(bs, &cfg); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return &cfg.GUI, nil
+}
+
+// resolveInstance returns a copy of the instance settings with the GUI
+// address, scheme and certificate filled in from Syncthing's config.xml,
+// if given and not set explicitly.
+func resolveInstance(inst *config.SyncthingInstance) (*config.SyncthingInstance, error) {
+	if inst.SyncthingConfigXml == "" {
+		return inst, nil
+	}
+	gui, err := readGUIConfig(inst.SyncthingConfigXml)
+	if err != nil {
+		return nil, fmt.Errorf("reading Syncthing config: %w", err)
+	}
+
+	inst = proto.Clone(inst).(*config.SyncthingInstance)
+	if inst.Address == "" {
+		inst.Address = connectAddress(gui.Address)
+	}
+	if inst.Scheme == "" && gui.TLS {
+		inst.Scheme = "https"
+		if inst.CaFile == "" && inst.CertFingerprint == "" && !inst.InsecureSkipVerify {
+			// Pin the self signed GUI certificate that Syncthing keeps
+			// next to its config.
+			fp, err := certFingerprint(filepath.Join(filepath.Dir(inst.SyncthingConfigXml), "https-cert.pem"))
+			if err != nil {
+				return nil, fmt.Errorf("reading Syncthing GUI certificate: %w", err)
+			}
+			inst.CertFingerprint = fp
+		}
+	}
+	return inst, nil
+}
+
+// connectAddress turns a listen address into one we can connect to.
+func connectAddress(addr string) string {
+	if strings.HasPrefix(addr, unixPrefix) {
+		return addr
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	switch host {
+	case "", "0.0.0.0", "::":
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+func certFingerprint(path string) (string, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	block, _ := pem.Decode(bs)
+	if block == nil {
+		return "", fmt.Errorf("no certificate in %s", path)
+	}
+	return protocol.NewDeviceID(block.Bytes).String(), nil
+}
+
+// apiKeySource provides the API key for an instance. A key read from a
+// file is read again whenever the file changes, so that we pick up a key
+// regenerated by Syncthing.
+type apiKeySource struct {
+	mut     sync.Mutex
+	key     string
+	path    string
+	read    func(path string) (string, error)
+	modTime time.Time
+}
+
+func newAPIKeySource(inst *config.SyncthingInstance) (*apiKeySource, error) {
+	switch {
+	case inst.ApiKey != "":
+		return &apiKeySource{key: inst.ApiKey}, nil
+
+	case inst.ApiKeyEnv != "":
+		key := os.Getenv(inst.ApiKeyEnv)
+		if key == "" {
+			return nil, fmt.Errorf("environment variable %s is not set", inst.ApiKeyEnv)
+		}
+		return &apiKeySource{key: key}, nil
+
+	case inst.ApiKeyFile != "":
+		return &apiKeySource{path: inst.ApiKeyFile, read: readKeyFile}, nil
+
+	case inst.SyncthingConfigXml != "":
+		return &apiKeySource{path: inst.SyncthingConfigXml, read: readConfigXMLKey}, nil
+	}
+	return nil, errors.New("no API key")
+}
+
+func (s *apiKeySource) Key() (string, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.path == "" {
+		return s.key, nil
+	}
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", fmt.Errorf("reading API key: %w", err)
+	}
+	if s.key != "" && info.ModTime().Equal(s.modTime) {
+		return s.key, nil
+	}
+	key, err := s.read(s.path)
+	if err != nil {
+		return "", fmt.Errorf("reading API key: %w", err)
+	}
+	if key == "" {
+		return "", fmt.Errorf("reading API key: %s contains no key", s.path)
+	}
+	s.key = key
+	s.modTime = info.ModTime()
+	return key, nil
+}
+
+func readKeyFile(path string) (string, error) {
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(bs)), nil
+}
+
+func readConfigXMLKey(path string) (string, error) {
+	gui, err := readGUIConfig(path)
+	if err != nil {
+		return "", err
+	}
+	return gui.APIKey, nil
+}
@@ -0,0 +1,102 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+const testConfigXML = `<configuration version="37">
+    <gui enabled="true" tls="%s" debugging="false">
+        <address>0.0.0.0:8384</address>
+        <apikey>%s</apikey>
+        <theme>default</theme>
+    </gui>
+</configuration>
+`
+
+func writeConfigXML(t *testing.T, path, tls, key string, mtime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(fmt.Sprintf(testConfigXML, tls, key)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestResolveConfigXML(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.xml")
+	writeConfigXML(t, path, "false", "abc123", time.Now())
+
+	inst, err := resolveInstance(&config.SyncthingInstance{SyncthingConfigXml: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.Address != "localhost:8384" || inst.URLScheme() != "http" {
+		t.Errorf("got %s://%s, want http://localhost:8384", inst.URLScheme(), inst.Address)
+	}
+
+	// An explicit address takes precedence
+	inst, err = resolveInstance(&config.SyncthingInstance{Address: "127.0.0.1:8081", SyncthingConfigXml: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.Address != "127.0.0.1:8081" {
+		t.Errorf("got address %s, want 127.0.0.1:8081", inst.Address)
+	}
+
+	// With TLS, but no certificate next to the config
+	writeConfigXML(t, path, "true", "abc123", time.Now())
+	if _, err := resolveInstance(&config.SyncthingInstance{SyncthingConfigXml: path}); err == nil {
+		t.Error("resolveInstance without GUI certificate returned nil error")
+	}
+	inst, err = resolveInstance(&config.SyncthingInstance{SyncthingConfigXml: path, InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inst.URLScheme() != "https" {
+		t.Errorf("got scheme %s, want https", inst.URLScheme())
+	}
+}
+
+func TestAPIKeySource(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.xml")
+	t0 := time.Now().Add(-time.Hour)
+	writeConfigXML(t, path, "false", "abc123", t0)
+
+	keys, err := newAPIKeySource(&config.SyncthingInstance{SyncthingConfigXml: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, err := keys.Key(); err != nil || key != "abc123" {
+		t.Errorf("Key() = %q, %v, want abc123", key, err)
+	}
+
+	// Syncthing regenerates the key
+	writeConfigXML(t, path, "false", "def456", t0.Add(time.Minute))
+	if key, err := keys.Key(); err != nil || key != "def456" {
+		t.Errorf("Key() = %q, %v, want def456", key, err)
+	}
+
+	// An explicit key takes precedence
+	keys, err = newAPIKeySource(&config.SyncthingInstance{ApiKey: "ghi789", SyncthingConfigXml: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key, err := keys.Key(); err != nil || key != "ghi789" {
+		t.Errorf("Key() = %q, %v, want ghi789", key, err)
+	}
+
+	if _, err := newAPIKeySource(&config.SyncthingInstance{ApiKeyEnv: "CONFIGD_TEST_UNSET_VARIABLE"}); err == nil {
+		t.Error("newAPIKeySource with unset variable returned nil error")
+	}
+}
@@ -0,0 +1,284 @@
+// Package apitest provides a fake Syncthing REST API for tests of code
+// that talks to Syncthing through an api.API.
+package apitest
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+// Syncthing is an in-memory Syncthing instance, serving the parts of the
+// REST API that configd uses.
+type Syncthing struct {
+	mut         sync.Mutex
+	myID        protocol.DeviceID
+	cfg         stconfig.Configuration
+	deviceStats map[protocol.DeviceID]api.DeviceStatistics
+	folderStats map[string]api.FolderStatistics
+	completion  map[string]map[protocol.DeviceID]api.FolderCompletion
+}
+
+// New starts a fake Syncthing with the given identity and configuration,
+// and returns it together with a running API client for it. Both are
+// stopped when the test ends.
+func New(t testing.TB, myID protocol.DeviceID, cfg stconfig.Configuration) (*Syncthing, *api.API) {
+	t.Helper()
+
+	st := &Syncthing{
+		myID:        myID,
+		cfg:         cfg,
+		deviceStats: make(map[protocol.DeviceID]api.DeviceStatistics),
+		folderStats: make(map[string]api.FolderStatistics),
+		completion:  make(map[string]map[protocol.DeviceID]api.FolderCompletion),
+	}
+	srv := httptest.NewServer(st)
+	t.Cleanup(srv.Close)
+
+	a, err := api.NewAPI(slog.Default(), &config.SyncthingInstance{
+		Address: strings.TrimPrefix(srv.URL, "http://"),
+		ApiKey:  "test",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	go func() { _ = a.Serve(ctx) }()
+
+	return st, a
+}
+
+// Config returns a copy of the current configuration.
+func (s *Syncthing) Config() stconfig.Configuration {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return s.cfg.Copy()
+}
+
+// SetMyID changes the identity of the instance, as if it was replaced.
+func (s *Syncthing) SetMyID(id protocol.DeviceID) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.myID = id
+}
+
+// SetDeviceStats sets the statistics returned for the device.
+func (s *Syncthing) SetDeviceStats(id protocol.DeviceID, stats api.DeviceStatistics) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.deviceStats[id] = stats
+}
+
+// SetFolderStats sets the statistics returned for the folder.
+func (s *Syncthing) SetFolderStats(id string, stats api.FolderStatistics) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.folderStats[id] = stats
+}
+
+// SetCompletion sets the completion returned for the folder and device.
+func (s *Syncthing) SetCompletion(folder string, device protocol.DeviceID, comp api.FolderCompletion) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	if s.completion[folder] == nil {
+		s.completion[folder] = make(map[protocol.DeviceID]api.FolderCompletion)
+	}
+	s.completion[folder][device] = comp
+}
+
+func (s *Syncthing) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Bearer test" {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	path := strings.TrimPrefix(r.URL.Path, "/rest/")
+	route := r.Method + " " + path
+	switch {
+	case route == "GET system/status":
+		reply(w, map[string]any{"myID": s.myID})
+	case route == "GET system/version":
+		reply(w, map[string]any{"version": "v1.27.10", "os": "linux", "arch": "amd64"})
+	case route == "GET stats/device":
+		reply(w, s.deviceStats)
+	case route == "GET stats/folder":
+		reply(w, s.folderStats)
+	case route == "GET db/completion":
+		dev, err := protocol.DeviceIDFromString(r.URL.Query().Get("device"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		comp, ok := s.completion[r.URL.Query().Get("folder")][dev]
+		if !ok {
+			comp.RemoteState = "unknown"
+		}
+		reply(w, comp)
+	case route == "GET config":
+		reply(w, s.cfg)
+	case route == "PUT config":
+		var cfg stconfig.Configuration
+		if decode(w, r, &cfg) {
+			s.cfg = cfg
+		}
+	case route == "PUT config/options":
+		decode(w, r, &s.cfg.Options)
+	case route == "PUT config/gui":
+		decode(w, r, &s.cfg.GUI)
+	case route == "POST config/folders":
+		var fld stconfig.FolderConfiguration
+		if decode(w, r, &fld) {
+			s.putFolder(fld)
+		}
+	case strings.HasPrefix(path, "config/devices/"):
+		s.serveDevice(w, r, strings.TrimPrefix(path, "config/devices/"))
+	case strings.HasPrefix(path, "config/folders/"):
+		s.serveFolder(w, r, strings.TrimPrefix(path, "config/folders/"))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Syncthing) serveDevice(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := protocol.DeviceIDFromString(idStr)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	idx := -1
+	for i, dev := range s.cfg.Devices {
+		if dev.DeviceID == id {
+			idx = i
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if idx < 0 {
+			http.NotFound(w, r)
+			return
+		}
+		reply(w, s.cfg.Devices[idx])
+	case http.MethodPut, http.MethodPatch:
+		var dev stconfig.DeviceConfiguration
+		if r.Method == http.MethodPatch {
+			if idx < 0 {
+				http.NotFound(w, r)
+				return
+			}
+			dev = s.cfg.Devices[idx]
+		}
+		if !decode(w, r, &dev) {
+			return
+		}
+		dev.DeviceID = id
+		if idx < 0 {
+			s.cfg.Devices = append(s.cfg.Devices, dev)
+		} else {
+			s.cfg.Devices[idx] = dev
+		}
+	case http.MethodDelete:
+		if idx < 0 {
+			http.NotFound(w, r)
+			return
+		}
+		s.cfg.Devices = append(s.cfg.Devices[:idx], s.cfg.Devices[idx+1:]...)
+		// Syncthing also stops sharing folders with a removed device
+		for i, fld := range s.cfg.Folders {
+			var devices []stconfig.FolderDeviceConfiguration
+			for _, dev := range fld.Devices {
+				if dev.DeviceID != id {
+					devices = append(devices, dev)
+				}
+			}
+			s.cfg.Folders[i].Devices = devices
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Syncthing) serveFolder(w http.ResponseWriter, r *http.Request, id string) {
+	idx := -1
+	for i, fld := range s.cfg.Folders {
+		if fld.ID == id {
+			idx = i
+		}
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		if idx < 0 {
+			http.NotFound(w, r)
+			return
+		}
+		reply(w, s.cfg.Folders[idx])
+	case http.MethodPut, http.MethodPatch:
+		var fld stconfig.FolderConfiguration
+		if r.Method == http.MethodPatch {
+			if idx < 0 {
+				http.NotFound(w, r)
+				return
+			}
+			fld = s.cfg.Folders[idx]
+		}
+		if !decode(w, r, &fld) {
+			return
+		}
+		fld.ID = id
+		s.putFolder(fld)
+	case http.MethodDelete:
+		if idx < 0 {
+			http.NotFound(w, r)
+			return
+		}
+		s.cfg.Folders = append(s.cfg.Folders[:idx], s.cfg.Folders[idx+1:]...)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Syncthing) putFolder(fld stconfig.FolderConfiguration) {
+	// Syncthing always shares a folder with the local device
+	local := false
+	for _, dev := range fld.Devices {
+		local = local || dev.DeviceID == s.myID
+	}
+	if !local {
+		fld.Devices = append([]stconfig.FolderDeviceConfiguration{{DeviceID: s.myID}}, fld.Devices...)
+	}
+	for i, cur := range s.cfg.Folders {
+		if cur.ID == fld.ID {
+			s.cfg.Folders[i] = fld
+			return
+		}
+	}
+	s.cfg.Folders = append(s.cfg.Folders, fld)
+}
+
+func reply(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	return true
+}
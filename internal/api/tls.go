@@ -0,0 +1,66 @@
+package api
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+var errFingerprintMismatch = errors.New("certificate fingerprint mismatch")
+
+// tlsConfig returns the TLS configuration for talking to the Syncthing
+// GUI. Certificates are verified against the system roots by default, a
+// CA file if given, or pinned to a given fingerprint.
+func tlsConfig(inst *config.SyncthingInstance) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: inst.InsecureSkipVerify,
+	}
+
+	if inst.CaFile != "" {
+		bs, err := os.ReadFile(inst.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(bs) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", inst.CaFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	if inst.CertFingerprint != "" {
+		fp, err := config.ParseFingerprint(inst.CertFingerprint)
+		if err != nil {
+			return nil, err
+		}
+		// The pinned certificate is typically self signed, with a
+		// hostname that doesn't match, so we do the verification
+		// ourselves.
+		cfg.InsecureSkipVerify = true
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			if len(cs.PeerCertificates) == 0 {
+				return errFingerprintMismatch
+			}
+			if sha256.Sum256(cs.PeerCertificates[0].Raw) != fp {
+				return errFingerprintMismatch
+			}
+			return nil
+		}
+	}
+
+	if inst.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(inst.ClientCertFile, inst.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
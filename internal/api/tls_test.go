@@ -0,0 +1,58 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+func TestTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+
+	cert := srv.Certificate()
+	fp := sha256.Sum256(cert.Raw)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		inst *config.SyncthingInstance
+		ok   bool
+	}{
+		{"system roots", &config.SyncthingInstance{}, false},
+		{"insecure", &config.SyncthingInstance{InsecureSkipVerify: true}, true},
+		{"ca file", &config.SyncthingInstance{CaFile: caFile}, true},
+		{"hex fingerprint", &config.SyncthingInstance{CertFingerprint: hex.EncodeToString(fp[:])}, true},
+		{"device ID fingerprint", &config.SyncthingInstance{CertFingerprint: protocol.NewDeviceID(cert.Raw).String()}, true},
+		{"wrong fingerprint", &config.SyncthingInstance{CertFingerprint: protocol.LocalDeviceID.String()}, false},
+	}
+	for _, c := range cases {
+		cfg, err := tlsConfig(c.inst)
+		if err != nil {
+			t.Fatalf("%s: tlsConfig: %v", c.name, err)
+		}
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: cfg}}
+		resp, err := client.Get(srv.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		if c.ok && err != nil {
+			t.Errorf("%s: request failed: %v", c.name, err)
+		} else if !c.ok && err == nil {
+			t.Errorf("%s: request succeeded, want failure", c.name)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package bandwidth applies the bandwidth schedules of patterns to the
+// devices they have accepted.
+package bandwidth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+const retryInterval = 10 * time.Second
+
+type Scheduler struct {
+	log      *slog.Logger
+	api      *api.API
+	cfg      *config.Configuration
+	state    *state.Store
+	instance *config.SyncthingInstance
+}
+
+// New returns a Scheduler for the patterns whose instance selector matches
+// the instance.
+func New(log *slog.Logger, api *api.API, cfg *config.Configuration, state *state.Store, instance *config.SyncthingInstance) *Scheduler {
+	return &Scheduler{
+		log:      log.With("address", api.Address()),
+		api:      api,
+		cfg:      cfg,
+		state:    state,
+		instance: instance,
+	}
+}
+
+// Serve applies the limits in effect at startup, and again at every start
+// and end of a bandwidth window.
+func (s *Scheduler) Serve(ctx context.Context) error {
+	for {
+		now := time.Now()
+		wait := retryInterval
+		if err := s.apply(now); err != nil {
+			s.log.Error("Failed to apply bandwidth schedule", "error", err)
+		} else if next := s.next(now); !next.IsZero() {
+			s.log.Debug("Next bandwidth change", "at", next)
+			wait = time.Until(next)
+		} else {
+			wait = 24 * time.Hour
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (s *Scheduler) String() string {
+	return fmt.Sprintf("bandwidthScheduler(%s)@%p", s.api.Address(), s)
+}
+
+// next returns the time of the next bandwidth change of any pattern.
+func (s *Scheduler) next(now time.Time) time.Time {
+	var res time.Time
+	for _, pat := range s.cfg.Pattern {
+		if !s.instance.Matches(pat.InstanceSelector) {
+			continue
+		}
+		if n := pat.NextBandwidthChange(now); !n.IsZero() && (res.IsZero() || n.Before(res)) {
+			res = n
+		}
+	}
+	return res
+}
+
+// apply sets the limits in effect at now on every device managed by a
+// pattern with a bandwidth schedule.
+func (s *Scheduler) apply(now time.Time) error {
+	return s.api.InConfigTx(func(tx *api.ConfigTx) error {
+		status, err := tx.GetSystemStatus()
+		if err != nil {
+			return err
+		}
+		if err := s.api.VerifyIdentity(status.MyID); err != nil {
+			return err
+		}
+		changes := limitChanges(tx.Config, s.state.Instance(status.MyID), s.cfg, s.instance, now)
+		updated := 0
+		for _, c := range changes {
+			l := s.log.With("device", c.id, "pattern", c.pattern, "maxSendKbps", c.send, "maxRecvKbps", c.recv)
+			err := tx.PatchDevice(c.id, map[string]any{
+				"maxSendKbps": c.send,
+				"maxRecvKbps": c.recv,
+			})
+			if err != nil {
+				l.Error("Failed to set bandwidth limits", "error", err)
+				continue
+			}
+			l.Debug("Set bandwidth limits")
+			updated++
+		}
+		if updated > 0 {
+			s.log.Info("Applied bandwidth schedule", "devices", updated)
+		}
+		return nil
+	})
+}
+
+type limitChange struct {
+	id         protocol.DeviceID
+	pattern    string
+	send, recv int
+}
+
+// limitChanges returns the managed devices whose limits differ from those
+// their pattern's schedule has in effect at now.
+func limitChanges(cfg *stconfig.Configuration, managed state.Instance, patterns *config.Configuration, instance *config.SyncthingInstance, now time.Time) []limitChange {
+	var res []limitChange
+	for id, dev := range managed.Devices {
+		pat := patterns.PatternByName(dev.Pattern)
+		if pat == nil || len(pat.BandwidthSchedule) == 0 || !instance.Matches(pat.InstanceSelector) {
+			continue
+		}
+		cur, _, ok := cfg.Device(id)
+		if !ok {
+			continue
+		}
+		want := pat.DeviceSettings(now)
+		if cur.MaxSendKbps != want.MaxSendKbps || cur.MaxRecvKbps != want.MaxRecvKbps {
+			res = append(res, limitChange{id: id, pattern: dev.Pattern, send: want.MaxSendKbps, recv: want.MaxRecvKbps})
+		}
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].id.Compare(res[b].id) < 0
+	})
+	return res
+}
@@ -0,0 +1,60 @@
+package bandwidth
+
+import (
+	"testing"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"google.golang.org/protobuf/proto"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+func TestLimitChanges(t *testing.T) {
+	t.Parallel()
+
+	store1 := protocol.DeviceID{1}
+	store2 := protocol.DeviceID{2}
+	other := protocol.DeviceID{3}
+
+	patterns := &config.Configuration{
+		Pattern: []*config.DevicePattern{
+			{
+				Name:     "stores",
+				Settings: &config.DeviceConfiguration{MaxSendKbps: 10000},
+				BandwidthSchedule: []*config.BandwidthWindow{
+					{Weekday: []string{"mon-fri"}, Start: "08:00", End: "18:00", MaxSendKbps: proto.Int32(500)},
+				},
+			},
+			{Name: "other"},
+		},
+	}
+	cfg := &stconfig.Configuration{
+		Devices: []stconfig.DeviceConfiguration{
+			{DeviceID: store1, MaxSendKbps: 10000},
+			{DeviceID: store2, MaxSendKbps: 500},
+			{DeviceID: other, MaxSendKbps: 10000},
+		},
+	}
+	managed := state.Instance{
+		Devices: map[protocol.DeviceID]state.Device{
+			store1: {Pattern: "stores"},
+			store2: {Pattern: "stores"},
+			other:  {Pattern: "other"},
+		},
+	}
+	inst := &config.SyncthingInstance{}
+
+	// Friday during business hours
+	changes := limitChanges(cfg, managed, patterns, inst, time.Date(2024, 8, 16, 12, 0, 0, 0, time.UTC))
+	if len(changes) != 1 || changes[0].id != store1 || changes[0].send != 500 {
+		t.Errorf("unexpected changes during window %+v", changes)
+	}
+
+	// Friday evening
+	changes = limitChanges(cfg, managed, patterns, inst, time.Date(2024, 8, 16, 19, 0, 0, 0, time.UTC))
+	if len(changes) != 1 || changes[0].id != store2 || changes[0].send != 10000 {
+		t.Errorf("unexpected changes outside window %+v", changes)
+	}
+}
@@ -0,0 +1,193 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func (w *BandwidthWindow) Validate() error {
+	if _, err := w.days(); err != nil {
+		return err
+	}
+	if _, err := parseClock(w.Start); err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+	if _, err := parseClock(w.End); err != nil {
+		return fmt.Errorf("end: %w", err)
+	}
+	if _, err := w.Location(); err != nil {
+		return err
+	}
+	if w.GetMaxSendKbps() < 0 || w.GetMaxRecvKbps() < 0 {
+		return errors.New("bandwidth limits must not be negative")
+	}
+	return nil
+}
+
+// Location returns the time zone for the window, by default UTC.
+func (w *BandwidthWindow) Location() (*time.Location, error) {
+	if w.GetTimezone() == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(w.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	return loc, nil
+}
+
+// Active returns true if t is within the window. A window that ends
+// before it starts continues into the next day, and one that ends when it
+// starts lasts all day. The weekdays are those the window starts on.
+func (w *BandwidthWindow) Active(t time.Time) bool {
+	days, err := w.days()
+	if err != nil {
+		return false
+	}
+	loc, err := w.Location()
+	if err != nil {
+		return false
+	}
+	start, _ := parseClock(w.Start)
+	end, _ := parseClock(w.End)
+
+	t = t.In(loc)
+	now := t.Hour()*60 + t.Minute()
+	today := days&(1<<uint(t.Weekday())) != 0
+	yesterday := days&(1<<uint(t.AddDate(0, 0, -1).Weekday())) != 0
+	switch {
+	case start < end:
+		return today && now >= start && now < end
+	case start > end:
+		return today && now >= start || yesterday && now < end
+	default:
+		return today
+	}
+}
+
+// next returns the first start or end of the window after t.
+func (w *BandwidthWindow) next(t time.Time) time.Time {
+	loc, err := w.Location()
+	if err != nil {
+		return time.Time{}
+	}
+	start, _ := parseClock(w.Start)
+	end, _ := parseClock(w.End)
+
+	t = t.In(loc)
+	var res time.Time
+	for d := 0; d <= 1; d++ {
+		for _, m := range []int{start, end} {
+			c := time.Date(t.Year(), t.Month(), t.Day()+d, m/60, m%60, 0, 0, loc)
+			if c.After(t) && (res.IsZero() || c.Before(res)) {
+				res = c
+			}
+		}
+	}
+	return res
+}
+
+// days returns the weekdays of the window as a bit set.
+func (w *BandwidthWindow) days() (uint8, error) {
+	if len(w.GetWeekday()) == 0 {
+		return 0x7f, nil
+	}
+	var res uint8
+	for _, wd := range w.Weekday {
+		first, last, isRange := strings.Cut(strings.ToLower(wd), "-")
+		if !isRange {
+			last = first
+		}
+		from, ok1 := weekdays[first]
+		to, ok2 := weekdays[last]
+		if !ok1 || !ok2 {
+			return 0, fmt.Errorf("weekday %q: expected mon, tue, ... or a range like mon-fri", wd)
+		}
+		for d := from; ; d = (d + 1) % 7 {
+			res |= 1 << uint(d)
+			if d == to {
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// parseClock parses a time of day as "HH:MM", returning the minutes since
+// midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("time of day %q: expected HH:MM", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// DeviceSettings returns the Syncthing configuration for devices accepted
+// by the pattern, with the bandwidth limits in effect at t. A limit not
+// set by the active window is taken from the pattern's settings.
+func (p *DevicePattern) DeviceSettings(t time.Time) stconfig.DeviceConfiguration {
+	cfg := p.GetSettings().SyncthingConfig()
+	if w := p.activeWindow(t); w != nil {
+		if w.MaxSendKbps != nil {
+			cfg.MaxSendKbps = int(*w.MaxSendKbps)
+		}
+		if w.MaxRecvKbps != nil {
+			cfg.MaxRecvKbps = int(*w.MaxRecvKbps)
+		}
+	}
+	return cfg
+}
+
+// activeWindow returns the bandwidth window in effect at t, or nil if
+// there is none.
+func (p *DevicePattern) activeWindow(t time.Time) *BandwidthWindow {
+	for _, w := range p.GetBandwidthSchedule() {
+		if w.Active(t) {
+			return w
+		}
+	}
+	return nil
+}
+
+// ScheduledLimits returns the JSON names of the device bandwidth limits
+// set by the window in effect at t. These are given even when zero
+// (unlimited), which the pattern's settings cannot express.
+func (p *DevicePattern) ScheduledLimits(t time.Time) []string {
+	var res []string
+	if w := p.activeWindow(t); w != nil {
+		if w.MaxSendKbps != nil {
+			res = append(res, "maxSendKbps")
+		}
+		if w.MaxRecvKbps != nil {
+			res = append(res, "maxRecvKbps")
+		}
+	}
+	return res
+}
+
+// NextBandwidthChange returns the first time after t when a bandwidth
+// window starts or ends, or the zero time if the pattern has no schedule.
+func (p *DevicePattern) NextBandwidthChange(t time.Time) time.Time {
+	var res time.Time
+	for _, w := range p.GetBandwidthSchedule() {
+		if n := w.next(t); !n.IsZero() && (res.IsZero() || n.Before(res)) {
+			res = n
+		}
+	}
+	return res
+}
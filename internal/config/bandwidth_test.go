@@ -0,0 +1,117 @@
+package config
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBandwidthWindowActive(t *testing.T) {
+	t.Parallel()
+
+	// 2024-08-16 is a Friday
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, 8, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	cases := []struct {
+		name   string
+		window *BandwidthWindow
+		t      time.Time
+		active bool
+	}{
+		{"business hours", &BandwidthWindow{Weekday: []string{"mon-fri"}, Start: "08:00", End: "18:00"}, at(16, 8, 0), true},
+		{"before start", &BandwidthWindow{Weekday: []string{"mon-fri"}, Start: "08:00", End: "18:00"}, at(16, 7, 59), false},
+		{"at end", &BandwidthWindow{Weekday: []string{"mon-fri"}, Start: "08:00", End: "18:00"}, at(16, 18, 0), false},
+		{"weekend", &BandwidthWindow{Weekday: []string{"mon-fri"}, Start: "08:00", End: "18:00"}, at(17, 12, 0), false},
+		{"wrapping range", &BandwidthWindow{Weekday: []string{"sat-mon"}, Start: "08:00", End: "18:00"}, at(18, 12, 0), true},
+		{"every day", &BandwidthWindow{Start: "08:00", End: "18:00"}, at(18, 12, 0), true},
+		{"overnight, evening", &BandwidthWindow{Weekday: []string{"fri"}, Start: "22:00", End: "06:00"}, at(16, 23, 0), true},
+		{"overnight, next morning", &BandwidthWindow{Weekday: []string{"fri"}, Start: "22:00", End: "06:00"}, at(17, 5, 0), true},
+		{"overnight, morning before", &BandwidthWindow{Weekday: []string{"fri"}, Start: "22:00", End: "06:00"}, at(16, 5, 0), false},
+		{"all day", &BandwidthWindow{Weekday: []string{"fri"}, Start: "00:00", End: "00:00"}, at(16, 23, 59), true},
+		{"time zone", &BandwidthWindow{Start: "08:00", End: "18:00", Timezone: "America/New_York"}, at(16, 23, 0), false},
+		{"time zone, active", &BandwidthWindow{Start: "08:00", End: "18:00", Timezone: "America/New_York"}, at(16, 21, 0), true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			if err := tc.window.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if got := tc.window.Active(tc.t); got != tc.active {
+				t.Errorf("Active(%v) = %v, want %v", tc.t, got, tc.active)
+			}
+		})
+	}
+}
+
+func TestBandwidthWindowValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, w := range []*BandwidthWindow{
+		{Start: "8", End: "18:00"},
+		{Start: "08:00", End: "24:00"},
+		{Weekday: []string{"monday"}, Start: "08:00", End: "18:00"},
+		{Start: "08:00", End: "18:00", Timezone: "Nowhere/Special"},
+		{Start: "08:00", End: "18:00", MaxSendKbps: proto.Int32(-1)},
+	} {
+		if err := w.Validate(); err == nil {
+			t.Errorf("expected error for %v", w)
+		}
+	}
+}
+
+func TestDevicePatternBandwidth(t *testing.T) {
+	t.Parallel()
+
+	pat := &DevicePattern{
+		Settings: &DeviceConfiguration{MaxSendKbps: 10000, MaxRecvKbps: 3000},
+		BandwidthSchedule: []*BandwidthWindow{
+			{Weekday: []string{"mon-fri"}, Start: "08:00", End: "18:00", MaxSendKbps: proto.Int32(500), MaxRecvKbps: proto.Int32(1000)},
+			{Start: "06:00", End: "20:00", MaxSendKbps: proto.Int32(2000)},
+			{Start: "20:00", End: "22:00", MaxSendKbps: proto.Int32(0)},
+		},
+	}
+	friday := time.Date(2024, 8, 16, 12, 0, 0, 0, time.UTC)
+
+	if cfg := pat.DeviceSettings(friday); cfg.MaxSendKbps != 500 || cfg.MaxRecvKbps != 1000 {
+		t.Errorf("got limits %d/%d during first window", cfg.MaxSendKbps, cfg.MaxRecvKbps)
+	}
+	// The receive limit isn't set by the second window, and is inherited
+	// from the pattern
+	if cfg := pat.DeviceSettings(friday.Add(7 * time.Hour)); cfg.MaxSendKbps != 2000 || cfg.MaxRecvKbps != 3000 {
+		t.Errorf("got limits %d/%d during second window", cfg.MaxSendKbps, cfg.MaxRecvKbps)
+	}
+	// An explicit zero lifts the limit
+	if cfg := pat.DeviceSettings(friday.Add(9 * time.Hour)); cfg.MaxSendKbps != 0 || cfg.MaxRecvKbps != 3000 {
+		t.Errorf("got limits %d/%d during third window", cfg.MaxSendKbps, cfg.MaxRecvKbps)
+	}
+	if cfg := pat.DeviceSettings(friday.Add(11 * time.Hour)); cfg.MaxSendKbps != 10000 || cfg.MaxRecvKbps != 3000 {
+		t.Errorf("got limits %d/%d outside windows", cfg.MaxSendKbps, cfg.MaxRecvKbps)
+	}
+
+	if got := pat.ScheduledLimits(friday.Add(7 * time.Hour)); !slices.Equal(got, []string{"maxSendKbps"}) {
+		t.Errorf("got scheduled limits %v during second window", got)
+	}
+	if got := pat.ScheduledLimits(friday.Add(11 * time.Hour)); got != nil {
+		t.Errorf("got scheduled limits %v outside windows", got)
+	}
+
+	if next := pat.NextBandwidthChange(friday); !next.Equal(friday.Add(6 * time.Hour)) {
+		t.Errorf("next change %v, want 18:00", next)
+	}
+	if next := pat.NextBandwidthChange(friday.Add(9 * time.Hour)); !next.Equal(friday.Add(10 * time.Hour)) {
+		t.Errorf("next change %v, want 22:00", next)
+	}
+	if next := pat.NextBandwidthChange(friday.Add(11 * time.Hour)); !next.Equal(friday.Add(18 * time.Hour)) {
+		t.Errorf("next change %v, want 06:00 the next day", next)
+	}
+	if next := (&DevicePattern{}).NextBandwidthChange(friday); !next.IsZero() {
+		t.Errorf("next change %v without schedule", next)
+	}
+}
@@ -20,6 +20,52 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type HookFailurePolicy int32
+
+const (
+	HookFailurePolicy_FAILURE_LOG      HookFailurePolicy = 0
+	HookFailurePolicy_FAILURE_ROLLBACK HookFailurePolicy = 1 // on_accept only; undo the configuration changes
+)
+
+// Enum value maps for HookFailurePolicy.
+var (
+	HookFailurePolicy_name = map[int32]string{
+		0: "FAILURE_LOG",
+		1: "FAILURE_ROLLBACK",
+	}
+	HookFailurePolicy_value = map[string]int32{
+		"FAILURE_LOG":      0,
+		"FAILURE_ROLLBACK": 1,
+	}
+)
+
+func (x HookFailurePolicy) Enum() *HookFailurePolicy {
+	p := new(HookFailurePolicy)
+	*p = x
+	return p
+}
+
+func (x HookFailurePolicy) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HookFailurePolicy) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_config_proto_enumTypes[0].Descriptor()
+}
+
+func (HookFailurePolicy) Type() protoreflect.EnumType {
+	return &file_proto_config_proto_enumTypes[0]
+}
+
+func (x HookFailurePolicy) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HookFailurePolicy.Descriptor instead.
+func (HookFailurePolicy) EnumDescriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{0}
+}
+
 type FolderType int32
 
 const (
@@ -56,11 +102,11 @@ func (x FolderType) String() string {
 }
 
 func (FolderType) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_config_proto_enumTypes[0].Descriptor()
+	return file_proto_config_proto_enumTypes[1].Descriptor()
 }
 
 func (FolderType) Type() protoreflect.EnumType {
-	return &file_proto_config_proto_enumTypes[0]
+	return &file_proto_config_proto_enumTypes[1]
 }
 
 func (x FolderType) Number() protoreflect.EnumNumber {
@@ -69,7 +115,7 @@ func (x FolderType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use FolderType.Descriptor instead.
 func (FolderType) EnumDescriptor() ([]byte, []int) {
-	return file_proto_config_proto_rawDescGZIP(), []int{0}
+	return file_proto_config_proto_rawDescGZIP(), []int{1}
 }
 
 type PullOrder int32
@@ -114,11 +160,11 @@ func (x PullOrder) String() string {
 }
 
 func (PullOrder) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_config_proto_enumTypes[1].Descriptor()
+	return file_proto_config_proto_enumTypes[2].Descriptor()
 }
 
 func (PullOrder) Type() protoreflect.EnumType {
-	return &file_proto_config_proto_enumTypes[1]
+	return &file_proto_config_proto_enumTypes[2]
 }
 
 func (x PullOrder) Number() protoreflect.EnumNumber {
@@ -127,7 +173,7 @@ func (x PullOrder) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use PullOrder.Descriptor instead.
 func (PullOrder) EnumDescriptor() ([]byte, []int) {
-	return file_proto_config_proto_rawDescGZIP(), []int{1}
+	return file_proto_config_proto_rawDescGZIP(), []int{2}
 }
 
 type BlockPullOrder int32
@@ -163,11 +209,11 @@ func (x BlockPullOrder) String() string {
 }
 
 func (BlockPullOrder) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_config_proto_enumTypes[2].Descriptor()
+	return file_proto_config_proto_enumTypes[3].Descriptor()
 }
 
 func (BlockPullOrder) Type() protoreflect.EnumType {
-	return &file_proto_config_proto_enumTypes[2]
+	return &file_proto_config_proto_enumTypes[3]
 }
 
 func (x BlockPullOrder) Number() protoreflect.EnumNumber {
@@ -176,7 +222,7 @@ func (x BlockPullOrder) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use BlockPullOrder.Descriptor instead.
 func (BlockPullOrder) EnumDescriptor() ([]byte, []int) {
-	return file_proto_config_proto_rawDescGZIP(), []int{2}
+	return file_proto_config_proto_rawDescGZIP(), []int{3}
 }
 
 type CopyRangeMethod int32
@@ -221,11 +267,11 @@ func (x CopyRangeMethod) String() string {
 }
 
 func (CopyRangeMethod) Descriptor() protoreflect.EnumDescriptor {
-	return file_proto_config_proto_enumTypes[3].Descriptor()
+	return file_proto_config_proto_enumTypes[4].Descriptor()
 }
 
 func (CopyRangeMethod) Type() protoreflect.EnumType {
-	return &file_proto_config_proto_enumTypes[3]
+	return &file_proto_config_proto_enumTypes[4]
 }
 
 func (x CopyRangeMethod) Number() protoreflect.EnumNumber {
@@ -234,7 +280,7 @@ func (x CopyRangeMethod) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use CopyRangeMethod.Descriptor instead.
 func (CopyRangeMethod) EnumDescriptor() ([]byte, []int) {
-	return file_proto_config_proto_rawDescGZIP(), []int{3}
+	return file_proto_config_proto_rawDescGZIP(), []int{4}
 }
 
 type Configuration struct {
@@ -242,9 +288,19 @@ type Configuration struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Syncthing      []*SyncthingInstance `protobuf:"bytes,1,rep,name=syncthing,proto3" json:"syncthing,omitempty"`
-	Pattern        []*DevicePattern     `protobuf:"bytes,2,rep,name=pattern,proto3" json:"pattern,omitempty"`
-	GarbageCollect *GarbageCollection   `protobuf:"bytes,3,opt,name=garbage_collect,json=garbageCollect,proto3" json:"garbage_collect,omitempty"`
+	Syncthing           []*SyncthingInstance `protobuf:"bytes,1,rep,name=syncthing,proto3" json:"syncthing,omitempty"`
+	Pattern             []*DevicePattern     `protobuf:"bytes,2,rep,name=pattern,proto3" json:"pattern,omitempty"`
+	GarbageCollect      *GarbageCollection   `protobuf:"bytes,3,opt,name=garbage_collect,json=garbageCollect,proto3" json:"garbage_collect,omitempty"`
+	MaxVariableLength   int32                `protobuf:"varint,4,opt,name=max_variable_length,json=maxVariableLength,proto3" json:"max_variable_length,omitempty"` // default 64
+	Hooks               *Hooks               `protobuf:"bytes,5,opt,name=hooks,proto3" json:"hooks,omitempty"`
+	StateFile           string               `protobuf:"bytes,6,opt,name=state_file,json=stateFile,proto3" json:"state_file,omitempty"`
+	Admin               *AdminAPI            `protobuf:"bytes,7,opt,name=admin,proto3" json:"admin,omitempty"`
+	Discover            []*Discover          `protobuf:"bytes,8,rep,name=discover,proto3" json:"discover,omitempty"`
+	Device              []*DesiredDevice     `protobuf:"bytes,9,rep,name=device,proto3" json:"device,omitempty"`
+	Folder              []*DesiredFolder     `protobuf:"bytes,10,rep,name=folder,proto3" json:"folder,omitempty"`
+	ReconcileIntervalS  int32                `protobuf:"varint,11,opt,name=reconcile_interval_s,json=reconcileIntervalS,proto3" json:"reconcile_interval_s,omitempty"`      // default 300
+	DriftCheckIntervalS int32                `protobuf:"varint,12,opt,name=drift_check_interval_s,json=driftCheckIntervalS,proto3" json:"drift_check_interval_s,omitempty"` // default 3600, -1 to disable
+	Options             *Options             `protobuf:"bytes,13,opt,name=options,proto3" json:"options,omitempty"`
 }
 
 func (x *Configuration) Reset() {
@@ -300,17 +356,103 @@ func (x *Configuration) GetGarbageCollect() *GarbageCollection {
 	return nil
 }
 
-type SyncthingInstance struct {
+func (x *Configuration) GetMaxVariableLength() int32 {
+	if x != nil {
+		return x.MaxVariableLength
+	}
+	return 0
+}
+
+func (x *Configuration) GetHooks() *Hooks {
+	if x != nil {
+		return x.Hooks
+	}
+	return nil
+}
+
+func (x *Configuration) GetStateFile() string {
+	if x != nil {
+		return x.StateFile
+	}
+	return ""
+}
+
+func (x *Configuration) GetAdmin() *AdminAPI {
+	if x != nil {
+		return x.Admin
+	}
+	return nil
+}
+
+func (x *Configuration) GetDiscover() []*Discover {
+	if x != nil {
+		return x.Discover
+	}
+	return nil
+}
+
+func (x *Configuration) GetDevice() []*DesiredDevice {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *Configuration) GetFolder() []*DesiredFolder {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+func (x *Configuration) GetReconcileIntervalS() int32 {
+	if x != nil {
+		return x.ReconcileIntervalS
+	}
+	return 0
+}
+
+func (x *Configuration) GetDriftCheckIntervalS() int32 {
+	if x != nil {
+		return x.DriftCheckIntervalS
+	}
+	return 0
+}
+
+func (x *Configuration) GetOptions() *Options {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+// Options are Syncthing's global options, enforced on every instance.
+// Options that are not set are left alone.
+type Options struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	ApiKey  string `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
-}
-
-func (x *SyncthingInstance) Reset() {
-	*x = SyncthingInstance{}
+	ListenAddresses        []string `protobuf:"bytes,1,rep,name=listen_addresses,json=listenAddresses,proto3" json:"listen_addresses,omitempty"`
+	GlobalDiscoveryServers []string `protobuf:"bytes,2,rep,name=global_discovery_servers,json=globalDiscoveryServers,proto3" json:"global_discovery_servers,omitempty"`
+	GlobalDiscoveryEnabled *bool    `protobuf:"varint,3,opt,name=global_discovery_enabled,json=globalDiscoveryEnabled,proto3,oneof" json:"global_discovery_enabled,omitempty"`
+	LocalDiscoveryEnabled  *bool    `protobuf:"varint,4,opt,name=local_discovery_enabled,json=localDiscoveryEnabled,proto3,oneof" json:"local_discovery_enabled,omitempty"`
+	RelaysEnabled          *bool    `protobuf:"varint,5,opt,name=relays_enabled,json=relaysEnabled,proto3,oneof" json:"relays_enabled,omitempty"`
+	NatTraversalEnabled    *bool    `protobuf:"varint,6,opt,name=nat_traversal_enabled,json=natTraversalEnabled,proto3,oneof" json:"nat_traversal_enabled,omitempty"`
+	MaxSendKbps            *int32   `protobuf:"varint,7,opt,name=max_send_kbps,json=maxSendKbps,proto3,oneof" json:"max_send_kbps,omitempty"`
+	MaxRecvKbps            *int32   `protobuf:"varint,8,opt,name=max_recv_kbps,json=maxRecvKbps,proto3,oneof" json:"max_recv_kbps,omitempty"`
+	LimitBandwidthInLan    *bool    `protobuf:"varint,9,opt,name=limit_bandwidth_in_lan,json=limitBandwidthInLan,proto3,oneof" json:"limit_bandwidth_in_lan,omitempty"`
+	UsageReportingAccepted *int32   `protobuf:"varint,10,opt,name=usage_reporting_accepted,json=usageReportingAccepted,proto3,oneof" json:"usage_reporting_accepted,omitempty"` // -1 to decline
+	CrashReportingEnabled  *bool    `protobuf:"varint,11,opt,name=crash_reporting_enabled,json=crashReportingEnabled,proto3,oneof" json:"crash_reporting_enabled,omitempty"`
+	AutoUpgradeIntervalH   *int32   `protobuf:"varint,12,opt,name=auto_upgrade_interval_h,json=autoUpgradeIntervalH,proto3,oneof" json:"auto_upgrade_interval_h,omitempty"` // 0 to disable
+	StartBrowser           *bool    `protobuf:"varint,13,opt,name=start_browser,json=startBrowser,proto3,oneof" json:"start_browser,omitempty"`
+	AlwaysLocalNets        []string `protobuf:"bytes,14,rep,name=always_local_nets,json=alwaysLocalNets,proto3" json:"always_local_nets,omitempty"`
+	ConnectionLimitMax     *int32   `protobuf:"varint,15,opt,name=connection_limit_max,json=connectionLimitMax,proto3,oneof" json:"connection_limit_max,omitempty"`
+	Gui                    *GUI     `protobuf:"bytes,16,opt,name=gui,proto3" json:"gui,omitempty"`
+}
+
+func (x *Options) Reset() {
+	*x = Options{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_config_proto_msgTypes[1]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -318,13 +460,13 @@ func (x *SyncthingInstance) Reset() {
 	}
 }
 
-func (x *SyncthingInstance) String() string {
+func (x *Options) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SyncthingInstance) ProtoMessage() {}
+func (*Options) ProtoMessage() {}
 
-func (x *SyncthingInstance) ProtoReflect() protoreflect.Message {
+func (x *Options) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_config_proto_msgTypes[1]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -336,37 +478,141 @@ func (x *SyncthingInstance) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SyncthingInstance.ProtoReflect.Descriptor instead.
-func (*SyncthingInstance) Descriptor() ([]byte, []int) {
+// Deprecated: Use Options.ProtoReflect.Descriptor instead.
+func (*Options) Descriptor() ([]byte, []int) {
 	return file_proto_config_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *SyncthingInstance) GetAddress() string {
+func (x *Options) GetListenAddresses() []string {
 	if x != nil {
-		return x.Address
+		return x.ListenAddresses
 	}
-	return ""
+	return nil
 }
 
-func (x *SyncthingInstance) GetApiKey() string {
+func (x *Options) GetGlobalDiscoveryServers() []string {
 	if x != nil {
-		return x.ApiKey
+		return x.GlobalDiscoveryServers
 	}
-	return ""
+	return nil
 }
 
-type DevicePattern struct {
+func (x *Options) GetGlobalDiscoveryEnabled() bool {
+	if x != nil && x.GlobalDiscoveryEnabled != nil {
+		return *x.GlobalDiscoveryEnabled
+	}
+	return false
+}
+
+func (x *Options) GetLocalDiscoveryEnabled() bool {
+	if x != nil && x.LocalDiscoveryEnabled != nil {
+		return *x.LocalDiscoveryEnabled
+	}
+	return false
+}
+
+func (x *Options) GetRelaysEnabled() bool {
+	if x != nil && x.RelaysEnabled != nil {
+		return *x.RelaysEnabled
+	}
+	return false
+}
+
+func (x *Options) GetNatTraversalEnabled() bool {
+	if x != nil && x.NatTraversalEnabled != nil {
+		return *x.NatTraversalEnabled
+	}
+	return false
+}
+
+func (x *Options) GetMaxSendKbps() int32 {
+	if x != nil && x.MaxSendKbps != nil {
+		return *x.MaxSendKbps
+	}
+	return 0
+}
+
+func (x *Options) GetMaxRecvKbps() int32 {
+	if x != nil && x.MaxRecvKbps != nil {
+		return *x.MaxRecvKbps
+	}
+	return 0
+}
+
+func (x *Options) GetLimitBandwidthInLan() bool {
+	if x != nil && x.LimitBandwidthInLan != nil {
+		return *x.LimitBandwidthInLan
+	}
+	return false
+}
+
+func (x *Options) GetUsageReportingAccepted() int32 {
+	if x != nil && x.UsageReportingAccepted != nil {
+		return *x.UsageReportingAccepted
+	}
+	return 0
+}
+
+func (x *Options) GetCrashReportingEnabled() bool {
+	if x != nil && x.CrashReportingEnabled != nil {
+		return *x.CrashReportingEnabled
+	}
+	return false
+}
+
+func (x *Options) GetAutoUpgradeIntervalH() int32 {
+	if x != nil && x.AutoUpgradeIntervalH != nil {
+		return *x.AutoUpgradeIntervalH
+	}
+	return 0
+}
+
+func (x *Options) GetStartBrowser() bool {
+	if x != nil && x.StartBrowser != nil {
+		return *x.StartBrowser
+	}
+	return false
+}
+
+func (x *Options) GetAlwaysLocalNets() []string {
+	if x != nil {
+		return x.AlwaysLocalNets
+	}
+	return nil
+}
+
+func (x *Options) GetConnectionLimitMax() int32 {
+	if x != nil && x.ConnectionLimitMax != nil {
+		return *x.ConnectionLimitMax
+	}
+	return 0
+}
+
+func (x *Options) GetGui() *GUI {
+	if x != nil {
+		return x.Gui
+	}
+	return nil
+}
+
+// GUI are Syncthing's GUI and REST API settings, enforced on every
+// instance. Settings that are not set are left alone.
+type GUI struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Folder     []*FolderPattern     `protobuf:"bytes,1,rep,name=folder,proto3" json:"folder,omitempty"`
-	AcceptCidr []string             `protobuf:"bytes,2,rep,name=accept_cidr,json=acceptCidr,proto3" json:"accept_cidr,omitempty"`
-	Settings   *DeviceConfiguration `protobuf:"bytes,3,opt,name=settings,proto3" json:"settings,omitempty"`
+	Address               *string `protobuf:"bytes,1,opt,name=address,proto3,oneof" json:"address,omitempty"`
+	UseTls                *bool   `protobuf:"varint,2,opt,name=use_tls,json=useTls,proto3,oneof" json:"use_tls,omitempty"`
+	User                  *string `protobuf:"bytes,3,opt,name=user,proto3,oneof" json:"user,omitempty"`
+	Password              *string `protobuf:"bytes,4,opt,name=password,proto3,oneof" json:"password,omitempty"` // plain text, hashed by Syncthing
+	Theme                 *string `protobuf:"bytes,5,opt,name=theme,proto3,oneof" json:"theme,omitempty"`
+	InsecureAdminAccess   *bool   `protobuf:"varint,6,opt,name=insecure_admin_access,json=insecureAdminAccess,proto3,oneof" json:"insecure_admin_access,omitempty"`
+	InsecureSkipHostCheck *bool   `protobuf:"varint,7,opt,name=insecure_skip_host_check,json=insecureSkipHostCheck,proto3,oneof" json:"insecure_skip_host_check,omitempty"`
 }
 
-func (x *DevicePattern) Reset() {
-	*x = DevicePattern{}
+func (x *GUI) Reset() {
+	*x = GUI{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_config_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -374,13 +620,13 @@ func (x *DevicePattern) Reset() {
 	}
 }
 
-func (x *DevicePattern) String() string {
+func (x *GUI) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DevicePattern) ProtoMessage() {}
+func (*GUI) ProtoMessage() {}
 
-func (x *DevicePattern) ProtoReflect() protoreflect.Message {
+func (x *GUI) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_config_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -392,43 +638,75 @@ func (x *DevicePattern) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DevicePattern.ProtoReflect.Descriptor instead.
-func (*DevicePattern) Descriptor() ([]byte, []int) {
+// Deprecated: Use GUI.ProtoReflect.Descriptor instead.
+func (*GUI) Descriptor() ([]byte, []int) {
 	return file_proto_config_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *DevicePattern) GetFolder() []*FolderPattern {
-	if x != nil {
-		return x.Folder
+func (x *GUI) GetAddress() string {
+	if x != nil && x.Address != nil {
+		return *x.Address
 	}
-	return nil
+	return ""
 }
 
-func (x *DevicePattern) GetAcceptCidr() []string {
-	if x != nil {
-		return x.AcceptCidr
+func (x *GUI) GetUseTls() bool {
+	if x != nil && x.UseTls != nil {
+		return *x.UseTls
 	}
-	return nil
+	return false
 }
 
-func (x *DevicePattern) GetSettings() *DeviceConfiguration {
-	if x != nil {
-		return x.Settings
+func (x *GUI) GetUser() string {
+	if x != nil && x.User != nil {
+		return *x.User
 	}
-	return nil
+	return ""
 }
 
-type FolderPattern struct {
+func (x *GUI) GetPassword() string {
+	if x != nil && x.Password != nil {
+		return *x.Password
+	}
+	return ""
+}
+
+func (x *GUI) GetTheme() string {
+	if x != nil && x.Theme != nil {
+		return *x.Theme
+	}
+	return ""
+}
+
+func (x *GUI) GetInsecureAdminAccess() bool {
+	if x != nil && x.InsecureAdminAccess != nil {
+		return *x.InsecureAdminAccess
+	}
+	return false
+}
+
+func (x *GUI) GetInsecureSkipHostCheck() bool {
+	if x != nil && x.InsecureSkipHostCheck != nil {
+		return *x.InsecureSkipHostCheck
+	}
+	return false
+}
+
+// DesiredDevice is a device that should always exist on the selected
+// instances, with the given settings.
+type DesiredDevice struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Id       string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Settings *FolderConfiguration `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	Id               string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name             string               `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Settings         *DeviceConfiguration `protobuf:"bytes,3,opt,name=settings,proto3" json:"settings,omitempty"`
+	InstanceSelector map[string]string    `protobuf:"bytes,4,rep,name=instance_selector,json=instanceSelector,proto3" json:"instance_selector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
-func (x *FolderPattern) Reset() {
-	*x = FolderPattern{}
+func (x *DesiredDevice) Reset() {
+	*x = DesiredDevice{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_proto_config_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -436,13 +714,13 @@ func (x *FolderPattern) Reset() {
 	}
 }
 
-func (x *FolderPattern) String() string {
+func (x *DesiredDevice) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*FolderPattern) ProtoMessage() {}
+func (*DesiredDevice) ProtoMessage() {}
 
-func (x *FolderPattern) ProtoReflect() protoreflect.Message {
+func (x *DesiredDevice) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_config_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -454,23 +732,906 @@ func (x *FolderPattern) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use FolderPattern.ProtoReflect.Descriptor instead.
-func (*FolderPattern) Descriptor() ([]byte, []int) {
+// Deprecated: Use DesiredDevice.ProtoReflect.Descriptor instead.
+func (*DesiredDevice) Descriptor() ([]byte, []int) {
 	return file_proto_config_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *FolderPattern) GetId() string {
+func (x *DesiredDevice) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DesiredDevice) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DesiredDevice) GetSettings() *DeviceConfiguration {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *DesiredDevice) GetInstanceSelector() map[string]string {
+	if x != nil {
+		return x.InstanceSelector
+	}
+	return nil
+}
+
+// DesiredFolder is a folder that should always exist on the selected
+// instances, with the given settings and shared with the given devices.
+type DesiredFolder struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id               string               `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Settings         *FolderConfiguration `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	Device           []string             `protobuf:"bytes,3,rep,name=device,proto3" json:"device,omitempty"` // device IDs
+	InstanceSelector map[string]string    `protobuf:"bytes,4,rep,name=instance_selector,json=instanceSelector,proto3" json:"instance_selector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *DesiredFolder) Reset() {
+	*x = DesiredFolder{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DesiredFolder) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DesiredFolder) ProtoMessage() {}
+
+func (x *DesiredFolder) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DesiredFolder.ProtoReflect.Descriptor instead.
+func (*DesiredFolder) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DesiredFolder) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DesiredFolder) GetSettings() *FolderConfiguration {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *DesiredFolder) GetDevice() []string {
+	if x != nil {
+		return x.Device
+	}
+	return nil
+}
+
+func (x *DesiredFolder) GetInstanceSelector() map[string]string {
+	if x != nil {
+		return x.InstanceSelector
+	}
+	return nil
+}
+
+// Discover finds Syncthing instances by their home directories, reading
+// the GUI address and API key from each config.xml.
+type Discover struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Glob       string             `protobuf:"bytes,1,opt,name=glob,proto3" json:"glob,omitempty"`                               // matches Syncthing home directories
+	PathRegexp string             `protobuf:"bytes,2,opt,name=path_regexp,json=pathRegexp,proto3" json:"path_regexp,omitempty"` // named groups become instance labels
+	IntervalS  int32              `protobuf:"varint,3,opt,name=interval_s,json=intervalS,proto3" json:"interval_s,omitempty"`   // default 60
+	Settings   *SyncthingInstance `protobuf:"bytes,4,opt,name=settings,proto3" json:"settings,omitempty"`                       // TLS settings etc. for discovered instances
+}
+
+func (x *Discover) Reset() {
+	*x = Discover{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Discover) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Discover) ProtoMessage() {}
+
+func (x *Discover) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Discover.ProtoReflect.Descriptor instead.
+func (*Discover) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Discover) GetGlob() string {
+	if x != nil {
+		return x.Glob
+	}
+	return ""
+}
+
+func (x *Discover) GetPathRegexp() string {
+	if x != nil {
+		return x.PathRegexp
+	}
+	return ""
+}
+
+func (x *Discover) GetIntervalS() int32 {
+	if x != nil {
+		return x.IntervalS
+	}
+	return 0
+}
+
+func (x *Discover) GetSettings() *SyncthingInstance {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+type AdminAPI struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ListenAddress string `protobuf:"bytes,1,opt,name=listen_address,json=listenAddress,proto3" json:"listen_address,omitempty"` // default 127.0.0.1:8090
+	ApiKey        string `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`                      // required unless listening on loopback only
+}
+
+func (x *AdminAPI) Reset() {
+	*x = AdminAPI{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminAPI) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminAPI) ProtoMessage() {}
+
+func (x *AdminAPI) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminAPI.ProtoReflect.Descriptor instead.
+func (*AdminAPI) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AdminAPI) GetListenAddress() string {
+	if x != nil {
+		return x.ListenAddress
+	}
+	return ""
+}
+
+func (x *AdminAPI) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+type SyncthingInstance struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Address            string            `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	ApiKey             string            `protobuf:"bytes,2,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Scheme             string            `protobuf:"bytes,3,opt,name=scheme,proto3" json:"scheme,omitempty"`                                          // http or https; default https if any TLS option is set
+	CaFile             string            `protobuf:"bytes,4,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`                            // PEM CA certificate(s) to verify the GUI certificate
+	CertFingerprint    string            `protobuf:"bytes,5,opt,name=cert_fingerprint,json=certFingerprint,proto3" json:"cert_fingerprint,omitempty"` // SHA-256 of the GUI certificate, in hex or as a device ID
+	ClientCertFile     string            `protobuf:"bytes,6,opt,name=client_cert_file,json=clientCertFile,proto3" json:"client_cert_file,omitempty"`  // PEM client certificate
+	ClientKeyFile      string            `protobuf:"bytes,7,opt,name=client_key_file,json=clientKeyFile,proto3" json:"client_key_file,omitempty"`     // PEM client key
+	InsecureSkipVerify bool              `protobuf:"varint,8,opt,name=insecure_skip_verify,json=insecureSkipVerify,proto3" json:"insecure_skip_verify,omitempty"`
+	ApiKeyFile         string            `protobuf:"bytes,9,opt,name=api_key_file,json=apiKeyFile,proto3" json:"api_key_file,omitempty"`                                                              // file containing the API key
+	ApiKeyEnv          string            `protobuf:"bytes,10,opt,name=api_key_env,json=apiKeyEnv,proto3" json:"api_key_env,omitempty"`                                                                // environment variable containing the API key
+	SyncthingConfigXml string            `protobuf:"bytes,11,opt,name=syncthing_config_xml,json=syncthingConfigXml,proto3" json:"syncthing_config_xml,omitempty"`                                     // Syncthing's config.xml, for the GUI address and API key
+	Name               string            `protobuf:"bytes,12,opt,name=name,proto3" json:"name,omitempty"`                                                                                             // for messages and ${instance}; default is the address
+	Labels             map[string]string `protobuf:"bytes,13,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // matched by instance_selector, and template variables
+}
+
+func (x *SyncthingInstance) Reset() {
+	*x = SyncthingInstance{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SyncthingInstance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncthingInstance) ProtoMessage() {}
+
+func (x *SyncthingInstance) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncthingInstance.ProtoReflect.Descriptor instead.
+func (*SyncthingInstance) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *SyncthingInstance) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetApiKey() string {
+	if x != nil {
+		return x.ApiKey
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetScheme() string {
+	if x != nil {
+		return x.Scheme
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetCaFile() string {
+	if x != nil {
+		return x.CaFile
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetCertFingerprint() string {
+	if x != nil {
+		return x.CertFingerprint
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetClientCertFile() string {
+	if x != nil {
+		return x.ClientCertFile
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetClientKeyFile() string {
+	if x != nil {
+		return x.ClientKeyFile
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetInsecureSkipVerify() bool {
+	if x != nil {
+		return x.InsecureSkipVerify
+	}
+	return false
+}
+
+func (x *SyncthingInstance) GetApiKeyFile() string {
+	if x != nil {
+		return x.ApiKeyFile
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetApiKeyEnv() string {
+	if x != nil {
+		return x.ApiKeyEnv
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetSyncthingConfigXml() string {
+	if x != nil {
+		return x.SyncthingConfigXml
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SyncthingInstance) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type DevicePattern struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Folder           []*FolderPattern         `protobuf:"bytes,1,rep,name=folder,proto3" json:"folder,omitempty"`
+	AcceptCidr       []string                 `protobuf:"bytes,2,rep,name=accept_cidr,json=acceptCidr,proto3" json:"accept_cidr,omitempty"`
+	Settings         *DeviceConfiguration     `protobuf:"bytes,3,opt,name=settings,proto3" json:"settings,omitempty"`
+	PathRoot         string                   `protobuf:"bytes,4,opt,name=path_root,json=pathRoot,proto3" json:"path_root,omitempty"`
+	Name             string                   `protobuf:"bytes,5,opt,name=name,proto3" json:"name,omitempty"`
+	GarbageCollect   *GarbageCollectionPolicy `protobuf:"bytes,6,opt,name=garbage_collect,json=garbageCollect,proto3" json:"garbage_collect,omitempty"`
+	InstanceSelector map[string]string        `protobuf:"bytes,7,rep,name=instance_selector,json=instanceSelector,proto3" json:"instance_selector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // labels an instance must have for the pattern to apply
+	PropagateTo      map[string]string        `protobuf:"bytes,8,rep,name=propagate_to,json=propagateTo,proto3" json:"propagate_to,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`                // labels of sibling instances to add accepted devices to
+	Placement        string                   `protobuf:"bytes,9,opt,name=placement,proto3" json:"placement,omitempty"`                                                                                                                               // least_devices, least_folders or hash
+	// Re-apply the settings to drifted devices and folders. Settings are
+	// compared only when they differ from their default, so a setting given
+	// as its default (e.g. fs_watcher_disabled: false, or max_send_kbps: 0)
+	// is neither detected nor enforced; bandwidth limits set by an active
+	// window are always compared.
+	Enforce           bool               `protobuf:"varint,10,opt,name=enforce,proto3" json:"enforce,omitempty"`
+	BandwidthSchedule []*BandwidthWindow `protobuf:"bytes,11,rep,name=bandwidth_schedule,json=bandwidthSchedule,proto3" json:"bandwidth_schedule,omitempty"`
+	Ttl               string             `protobuf:"bytes,12,opt,name=ttl,proto3" json:"ttl,omitempty"`                                       // e.g. "72h"; accepted devices expire this long after being accepted
+	ExpireAction      string             `protobuf:"bytes,13,opt,name=expire_action,json=expireAction,proto3" json:"expire_action,omitempty"` // remove (default) or pause
+}
+
+func (x *DevicePattern) Reset() {
+	*x = DevicePattern{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DevicePattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DevicePattern) ProtoMessage() {}
+
+func (x *DevicePattern) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DevicePattern.ProtoReflect.Descriptor instead.
+func (*DevicePattern) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DevicePattern) GetFolder() []*FolderPattern {
+	if x != nil {
+		return x.Folder
+	}
+	return nil
+}
+
+func (x *DevicePattern) GetAcceptCidr() []string {
+	if x != nil {
+		return x.AcceptCidr
+	}
+	return nil
+}
+
+func (x *DevicePattern) GetSettings() *DeviceConfiguration {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *DevicePattern) GetPathRoot() string {
+	if x != nil {
+		return x.PathRoot
+	}
+	return ""
+}
+
+func (x *DevicePattern) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DevicePattern) GetGarbageCollect() *GarbageCollectionPolicy {
+	if x != nil {
+		return x.GarbageCollect
+	}
+	return nil
+}
+
+func (x *DevicePattern) GetInstanceSelector() map[string]string {
+	if x != nil {
+		return x.InstanceSelector
+	}
+	return nil
+}
+
+func (x *DevicePattern) GetPropagateTo() map[string]string {
+	if x != nil {
+		return x.PropagateTo
+	}
+	return nil
+}
+
+func (x *DevicePattern) GetPlacement() string {
+	if x != nil {
+		return x.Placement
+	}
+	return ""
+}
+
+func (x *DevicePattern) GetEnforce() bool {
+	if x != nil {
+		return x.Enforce
+	}
+	return false
+}
+
+func (x *DevicePattern) GetBandwidthSchedule() []*BandwidthWindow {
+	if x != nil {
+		return x.BandwidthSchedule
+	}
+	return nil
+}
+
+func (x *DevicePattern) GetTtl() string {
+	if x != nil {
+		return x.Ttl
+	}
+	return ""
+}
+
+func (x *DevicePattern) GetExpireAction() string {
+	if x != nil {
+		return x.ExpireAction
+	}
+	return ""
+}
+
+// BandwidthWindow sets the bandwidth limits of devices accepted by a
+// pattern during a time window. Outside of all windows the limits from the
+// pattern's settings apply; when windows overlap the first one wins.
+type BandwidthWindow struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Weekday     []string `protobuf:"bytes,1,rep,name=weekday,proto3" json:"weekday,omitempty"`                                     // "mon", "tue", ... or ranges like "mon-fri"; default every day
+	Start       string   `protobuf:"bytes,2,opt,name=start,proto3" json:"start,omitempty"`                                         // "HH:MM"
+	End         string   `protobuf:"bytes,3,opt,name=end,proto3" json:"end,omitempty"`                                             // "HH:MM", before start to end the next day
+	Timezone    string   `protobuf:"bytes,4,opt,name=timezone,proto3" json:"timezone,omitempty"`                                   // default UTC
+	MaxSendKbps *int32   `protobuf:"varint,5,opt,name=max_send_kbps,json=maxSendKbps,proto3,oneof" json:"max_send_kbps,omitempty"` // default from the pattern's settings
+	MaxRecvKbps *int32   `protobuf:"varint,6,opt,name=max_recv_kbps,json=maxRecvKbps,proto3,oneof" json:"max_recv_kbps,omitempty"` // default from the pattern's settings
+}
+
+func (x *BandwidthWindow) Reset() {
+	*x = BandwidthWindow{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BandwidthWindow) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BandwidthWindow) ProtoMessage() {}
+
+func (x *BandwidthWindow) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BandwidthWindow.ProtoReflect.Descriptor instead.
+func (*BandwidthWindow) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BandwidthWindow) GetWeekday() []string {
+	if x != nil {
+		return x.Weekday
+	}
+	return nil
+}
+
+func (x *BandwidthWindow) GetStart() string {
+	if x != nil {
+		return x.Start
+	}
+	return ""
+}
+
+func (x *BandwidthWindow) GetEnd() string {
+	if x != nil {
+		return x.End
+	}
+	return ""
+}
+
+func (x *BandwidthWindow) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *BandwidthWindow) GetMaxSendKbps() int32 {
+	if x != nil && x.MaxSendKbps != nil {
+		return *x.MaxSendKbps
+	}
+	return 0
+}
+
+func (x *BandwidthWindow) GetMaxRecvKbps() int32 {
+	if x != nil && x.MaxRecvKbps != nil {
+		return *x.MaxRecvKbps
+	}
+	return 0
+}
+
+type FolderPattern struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id              string                   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Settings        *FolderConfiguration     `protobuf:"bytes,2,opt,name=settings,proto3" json:"settings,omitempty"`
+	CreateDirectory *CreateDirectory         `protobuf:"bytes,3,opt,name=create_directory,json=createDirectory,proto3" json:"create_directory,omitempty"`
+	GarbageCollect  *GarbageCollectionPolicy `protobuf:"bytes,4,opt,name=garbage_collect,json=garbageCollect,proto3" json:"garbage_collect,omitempty"`
+}
+
+func (x *FolderPattern) Reset() {
+	*x = FolderPattern{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FolderPattern) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FolderPattern) ProtoMessage() {}
+
+func (x *FolderPattern) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FolderPattern.ProtoReflect.Descriptor instead.
+func (*FolderPattern) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *FolderPattern) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *FolderPattern) GetSettings() *FolderConfiguration {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+func (x *FolderPattern) GetCreateDirectory() *CreateDirectory {
+	if x != nil {
+		return x.CreateDirectory
+	}
+	return nil
+}
+
+func (x *FolderPattern) GetGarbageCollect() *GarbageCollectionPolicy {
+	if x != nil {
+		return x.GarbageCollect
+	}
+	return nil
+}
+
+// CreateDirectory makes configd create the folder directory before adding
+// the folder to Syncthing. This requires that configd and Syncthing share
+// the same storage.
+type CreateDirectory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Uid    *int32 `protobuf:"varint,1,opt,name=uid,proto3,oneof" json:"uid,omitempty"` // default is to not change ownership
+	Gid    *int32 `protobuf:"varint,2,opt,name=gid,proto3,oneof" json:"gid,omitempty"` // default is to not change group
+	Mode   string `protobuf:"bytes,3,opt,name=mode,proto3" json:"mode,omitempty"`      // octal, default "0700"
+	Marker bool   `protobuf:"varint,4,opt,name=marker,proto3" json:"marker,omitempty"` // create the folder marker as well
+}
+
+func (x *CreateDirectory) Reset() {
+	*x = CreateDirectory{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CreateDirectory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDirectory) ProtoMessage() {}
+
+func (x *CreateDirectory) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDirectory.ProtoReflect.Descriptor instead.
+func (*CreateDirectory) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CreateDirectory) GetUid() int32 {
+	if x != nil && x.Uid != nil {
+		return *x.Uid
+	}
+	return 0
+}
+
+func (x *CreateDirectory) GetGid() int32 {
+	if x != nil && x.Gid != nil {
+		return *x.Gid
+	}
+	return 0
+}
+
+func (x *CreateDirectory) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *CreateDirectory) GetMarker() bool {
+	if x != nil {
+		return x.Marker
+	}
+	return false
+}
+
+type Hooks struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	OnAccept      *Hook `protobuf:"bytes,1,opt,name=on_accept,json=onAccept,proto3" json:"on_accept,omitempty"`
+	OnDeny        *Hook `protobuf:"bytes,2,opt,name=on_deny,json=onDeny,proto3" json:"on_deny,omitempty"`
+	OnGcRemove    *Hook `protobuf:"bytes,3,opt,name=on_gc_remove,json=onGcRemove,proto3" json:"on_gc_remove,omitempty"`
+	MaxConcurrent int32 `protobuf:"varint,4,opt,name=max_concurrent,json=maxConcurrent,proto3" json:"max_concurrent,omitempty"` // default 4
+	OnGcAlert     *Hook `protobuf:"bytes,5,opt,name=on_gc_alert,json=onGcAlert,proto3" json:"on_gc_alert,omitempty"`
+}
+
+func (x *Hooks) Reset() {
+	*x = Hooks{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hooks) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hooks) ProtoMessage() {}
+
+func (x *Hooks) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hooks.ProtoReflect.Descriptor instead.
+func (*Hooks) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Hooks) GetOnAccept() *Hook {
+	if x != nil {
+		return x.OnAccept
+	}
+	return nil
+}
+
+func (x *Hooks) GetOnDeny() *Hook {
+	if x != nil {
+		return x.OnDeny
+	}
+	return nil
+}
+
+func (x *Hooks) GetOnGcRemove() *Hook {
+	if x != nil {
+		return x.OnGcRemove
+	}
+	return nil
+}
+
+func (x *Hooks) GetMaxConcurrent() int32 {
+	if x != nil {
+		return x.MaxConcurrent
+	}
+	return 0
+}
+
+func (x *Hooks) GetOnGcAlert() *Hook {
+	if x != nil {
+		return x.OnGcAlert
+	}
+	return nil
+}
+
+type Hook struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Command   []string          `protobuf:"bytes,1,rep,name=command,proto3" json:"command,omitempty"`
+	TimeoutS  int32             `protobuf:"varint,2,opt,name=timeout_s,json=timeoutS,proto3" json:"timeout_s,omitempty"` // default 60
+	OnFailure HookFailurePolicy `protobuf:"varint,3,opt,name=on_failure,json=onFailure,proto3,enum=config.HookFailurePolicy" json:"on_failure,omitempty"`
+}
+
+func (x *Hook) Reset() {
+	*x = Hook{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Hook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hook) ProtoMessage() {}
+
+func (x *Hook) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hook.ProtoReflect.Descriptor instead.
+func (*Hook) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Hook) GetCommand() []string {
+	if x != nil {
+		return x.Command
+	}
+	return nil
+}
+
+func (x *Hook) GetTimeoutS() int32 {
 	if x != nil {
-		return x.Id
+		return x.TimeoutS
 	}
-	return ""
+	return 0
 }
 
-func (x *FolderPattern) GetSettings() *FolderConfiguration {
+func (x *Hook) GetOnFailure() HookFailurePolicy {
 	if x != nil {
-		return x.Settings
+		return x.OnFailure
 	}
-	return nil
+	return HookFailurePolicy_FAILURE_LOG
 }
 
 type DeviceConfiguration struct {
@@ -490,7 +1651,7 @@ type DeviceConfiguration struct {
 func (x *DeviceConfiguration) Reset() {
 	*x = DeviceConfiguration{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_config_proto_msgTypes[4]
+		mi := &file_proto_config_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -503,7 +1664,7 @@ func (x *DeviceConfiguration) String() string {
 func (*DeviceConfiguration) ProtoMessage() {}
 
 func (x *DeviceConfiguration) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_config_proto_msgTypes[4]
+	mi := &file_proto_config_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -516,7 +1677,7 @@ func (x *DeviceConfiguration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeviceConfiguration.ProtoReflect.Descriptor instead.
 func (*DeviceConfiguration) Descriptor() ([]byte, []int) {
-	return file_proto_config_proto_rawDescGZIP(), []int{4}
+	return file_proto_config_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DeviceConfiguration) GetAddresses() []string {
@@ -611,7 +1772,7 @@ type FolderConfiguration struct {
 func (x *FolderConfiguration) Reset() {
 	*x = FolderConfiguration{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_config_proto_msgTypes[5]
+		mi := &file_proto_config_proto_msgTypes[15]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -624,7 +1785,7 @@ func (x *FolderConfiguration) String() string {
 func (*FolderConfiguration) ProtoMessage() {}
 
 func (x *FolderConfiguration) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_config_proto_msgTypes[5]
+	mi := &file_proto_config_proto_msgTypes[15]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -637,7 +1798,7 @@ func (x *FolderConfiguration) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FolderConfiguration.ProtoReflect.Descriptor instead.
 func (*FolderConfiguration) Descriptor() ([]byte, []int) {
-	return file_proto_config_proto_rawDescGZIP(), []int{5}
+	return file_proto_config_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *FolderConfiguration) GetLabel() string {
@@ -883,7 +2044,7 @@ type Size struct {
 func (x *Size) Reset() {
 	*x = Size{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_config_proto_msgTypes[6]
+		mi := &file_proto_config_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -896,7 +2057,7 @@ func (x *Size) String() string {
 func (*Size) ProtoMessage() {}
 
 func (x *Size) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_config_proto_msgTypes[6]
+	mi := &file_proto_config_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -909,7 +2070,7 @@ func (x *Size) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Size.ProtoReflect.Descriptor instead.
 func (*Size) Descriptor() ([]byte, []int) {
-	return file_proto_config_proto_rawDescGZIP(), []int{6}
+	return file_proto_config_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *Size) GetValue() float64 {
@@ -931,15 +2092,27 @@ type GarbageCollection struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	RunEveryS         int32 `protobuf:"varint,1,opt,name=run_every_s,json=runEveryS,proto3" json:"run_every_s,omitempty"`
-	UnseenDevicesDays int32 `protobuf:"varint,2,opt,name=unseen_devices_days,json=unseenDevicesDays,proto3" json:"unseen_devices_days,omitempty"`
-	UnsharedFolders   bool  `protobuf:"varint,3,opt,name=unshared_folders,json=unsharedFolders,proto3" json:"unshared_folders,omitempty"`
+	RunEveryS          int32             `protobuf:"varint,1,opt,name=run_every_s,json=runEveryS,proto3" json:"run_every_s,omitempty"`
+	UnseenDevicesDays  int32             `protobuf:"varint,2,opt,name=unseen_devices_days,json=unseenDevicesDays,proto3" json:"unseen_devices_days,omitempty"`
+	UnsharedFolders    bool              `protobuf:"varint,3,opt,name=unshared_folders,json=unsharedFolders,proto3" json:"unshared_folders,omitempty"`
+	OnlyManaged        bool              `protobuf:"varint,4,opt,name=only_managed,json=onlyManaged,proto3" json:"only_managed,omitempty"`
+	NeverSeenGraceDays int32             `protobuf:"varint,5,opt,name=never_seen_grace_days,json=neverSeenGraceDays,proto3" json:"never_seen_grace_days,omitempty"`
+	MaxRemovalsPerRun  int32             `protobuf:"varint,6,opt,name=max_removals_per_run,json=maxRemovalsPerRun,proto3" json:"max_removals_per_run,omitempty"`
+	MaxRemovalFraction float64           `protobuf:"fixed64,7,opt,name=max_removal_fraction,json=maxRemovalFraction,proto3" json:"max_removal_fraction,omitempty"`                                                                                // 0.0 - 1.0
+	Protect            []string          `protobuf:"bytes,8,rep,name=protect,proto3" json:"protect,omitempty"`                                                                                                                                    // device IDs (any form, or short IDs), folder IDs or globs
+	DataDisposition    string            `protobuf:"bytes,9,opt,name=data_disposition,json=dataDisposition,proto3" json:"data_disposition,omitempty"`                                                                                             // keep, delete, move_to:<dir>, tar_to:<dir>; delete is delayed by quarantine_days
+	QuarantineDays     int32             `protobuf:"varint,10,opt,name=quarantine_days,json=quarantineDays,proto3" json:"quarantine_days,omitempty"`                                                                                              // delay before data is deleted; default 7, never immediate
+	StaleSharesDays    int32             `protobuf:"varint,11,opt,name=stale_shares_days,json=staleSharesDays,proto3" json:"stale_shares_days,omitempty"`                                                                                         // unshare folders from inactive devices
+	Schedule           string            `protobuf:"bytes,12,opt,name=schedule,proto3" json:"schedule,omitempty"`                                                                                                                                 // cron expression, instead of run_every_s
+	Timezone           string            `protobuf:"bytes,13,opt,name=timezone,proto3" json:"timezone,omitempty"`                                                                                                                                 // for the schedule, default UTC
+	JitterS            int32             `protobuf:"varint,14,opt,name=jitter_s,json=jitterS,proto3" json:"jitter_s,omitempty"`                                                                                                                   // random delay added to each run
+	InstanceSelector   map[string]string `protobuf:"bytes,15,rep,name=instance_selector,json=instanceSelector,proto3" json:"instance_selector,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // labels an instance must have to be collected
 }
 
 func (x *GarbageCollection) Reset() {
 	*x = GarbageCollection{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_proto_config_proto_msgTypes[7]
+		mi := &file_proto_config_proto_msgTypes[17]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -952,7 +2125,7 @@ func (x *GarbageCollection) String() string {
 func (*GarbageCollection) ProtoMessage() {}
 
 func (x *GarbageCollection) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_config_proto_msgTypes[7]
+	mi := &file_proto_config_proto_msgTypes[17]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -965,7 +2138,7 @@ func (x *GarbageCollection) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GarbageCollection.ProtoReflect.Descriptor instead.
 func (*GarbageCollection) Descriptor() ([]byte, []int) {
-	return file_proto_config_proto_rawDescGZIP(), []int{7}
+	return file_proto_config_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GarbageCollection) GetRunEveryS() int32 {
@@ -989,11 +2162,184 @@ func (x *GarbageCollection) GetUnsharedFolders() bool {
 	return false
 }
 
+func (x *GarbageCollection) GetOnlyManaged() bool {
+	if x != nil {
+		return x.OnlyManaged
+	}
+	return false
+}
+
+func (x *GarbageCollection) GetNeverSeenGraceDays() int32 {
+	if x != nil {
+		return x.NeverSeenGraceDays
+	}
+	return 0
+}
+
+func (x *GarbageCollection) GetMaxRemovalsPerRun() int32 {
+	if x != nil {
+		return x.MaxRemovalsPerRun
+	}
+	return 0
+}
+
+func (x *GarbageCollection) GetMaxRemovalFraction() float64 {
+	if x != nil {
+		return x.MaxRemovalFraction
+	}
+	return 0
+}
+
+func (x *GarbageCollection) GetProtect() []string {
+	if x != nil {
+		return x.Protect
+	}
+	return nil
+}
+
+func (x *GarbageCollection) GetDataDisposition() string {
+	if x != nil {
+		return x.DataDisposition
+	}
+	return ""
+}
+
+func (x *GarbageCollection) GetQuarantineDays() int32 {
+	if x != nil {
+		return x.QuarantineDays
+	}
+	return 0
+}
+
+func (x *GarbageCollection) GetStaleSharesDays() int32 {
+	if x != nil {
+		return x.StaleSharesDays
+	}
+	return 0
+}
+
+func (x *GarbageCollection) GetSchedule() string {
+	if x != nil {
+		return x.Schedule
+	}
+	return ""
+}
+
+func (x *GarbageCollection) GetTimezone() string {
+	if x != nil {
+		return x.Timezone
+	}
+	return ""
+}
+
+func (x *GarbageCollection) GetJitterS() int32 {
+	if x != nil {
+		return x.JitterS
+	}
+	return 0
+}
+
+func (x *GarbageCollection) GetInstanceSelector() map[string]string {
+	if x != nil {
+		return x.InstanceSelector
+	}
+	return nil
+}
+
+// GarbageCollectionPolicy overrides the global garbage collection settings
+// for devices and folders created by a pattern.
+type GarbageCollectionPolicy struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	UnseenDevicesDays  *int32  `protobuf:"varint,1,opt,name=unseen_devices_days,json=unseenDevicesDays,proto3,oneof" json:"unseen_devices_days,omitempty"`
+	NeverSeenGraceDays *int32  `protobuf:"varint,2,opt,name=never_seen_grace_days,json=neverSeenGraceDays,proto3,oneof" json:"never_seen_grace_days,omitempty"`
+	UnsharedFolders    *bool   `protobuf:"varint,3,opt,name=unshared_folders,json=unsharedFolders,proto3,oneof" json:"unshared_folders,omitempty"`
+	Never              bool    `protobuf:"varint,4,opt,name=never,proto3" json:"never,omitempty"` // never garbage collect
+	DataDisposition    *string `protobuf:"bytes,5,opt,name=data_disposition,json=dataDisposition,proto3,oneof" json:"data_disposition,omitempty"`
+	StaleSharesDays    *int32  `protobuf:"varint,6,opt,name=stale_shares_days,json=staleSharesDays,proto3,oneof" json:"stale_shares_days,omitempty"`
+}
+
+func (x *GarbageCollectionPolicy) Reset() {
+	*x = GarbageCollectionPolicy{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_proto_config_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GarbageCollectionPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GarbageCollectionPolicy) ProtoMessage() {}
+
+func (x *GarbageCollectionPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_config_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GarbageCollectionPolicy.ProtoReflect.Descriptor instead.
+func (*GarbageCollectionPolicy) Descriptor() ([]byte, []int) {
+	return file_proto_config_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *GarbageCollectionPolicy) GetUnseenDevicesDays() int32 {
+	if x != nil && x.UnseenDevicesDays != nil {
+		return *x.UnseenDevicesDays
+	}
+	return 0
+}
+
+func (x *GarbageCollectionPolicy) GetNeverSeenGraceDays() int32 {
+	if x != nil && x.NeverSeenGraceDays != nil {
+		return *x.NeverSeenGraceDays
+	}
+	return 0
+}
+
+func (x *GarbageCollectionPolicy) GetUnsharedFolders() bool {
+	if x != nil && x.UnsharedFolders != nil {
+		return *x.UnsharedFolders
+	}
+	return false
+}
+
+func (x *GarbageCollectionPolicy) GetNever() bool {
+	if x != nil {
+		return x.Never
+	}
+	return false
+}
+
+func (x *GarbageCollectionPolicy) GetDataDisposition() string {
+	if x != nil && x.DataDisposition != nil {
+		return *x.DataDisposition
+	}
+	return ""
+}
+
+func (x *GarbageCollectionPolicy) GetStaleSharesDays() int32 {
+	if x != nil && x.StaleSharesDays != nil {
+		return *x.StaleSharesDays
+	}
+	return 0
+}
+
 var File_proto_config_proto protoreflect.FileDescriptor
 
 var file_proto_config_proto_rawDesc = []byte{
 	0x0a, 0x12, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0xbd, 0x01, 0x0a,
+	0x72, 0x6f, 0x74, 0x6f, 0x12, 0x06, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0xf7, 0x04, 0x0a,
 	0x0d, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x37,
 	0x0a, 0x09, 0x73, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x18, 0x01, 0x20, 0x03, 0x28,
 	0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x74,
@@ -1005,183 +2351,541 @@ var file_proto_config_proto_rawDesc = []byte{
 	0x61, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28,
 	0x0b, 0x32, 0x19, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x61, 0x72, 0x62, 0x61,
 	0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0e, 0x67, 0x61,
-	0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x22, 0x46, 0x0a, 0x11,
-	0x53, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x61,
-	0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x70,
-	0x69, 0x4b, 0x65, 0x79, 0x22, 0x98, 0x01, 0x0a, 0x0d, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50,
-	0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x2d, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
-	0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x52, 0x06, 0x66,
-	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x1f, 0x0a, 0x0b, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x5f,
-	0x63, 0x69, 0x64, 0x72, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65,
-	0x70, 0x74, 0x43, 0x69, 0x64, 0x72, 0x12, 0x37, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e,
-	0x67, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x22,
-	0x58, 0x0a, 0x0d, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
-	0x12, 0x37, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x46, 0x6f, 0x6c, 0x64,
-	0x65, 0x72, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x22, 0xa7, 0x02, 0x0a, 0x13, 0x44, 0x65,
-	0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x03,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12,
-	0x29, 0x0a, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x6e, 0x65, 0x74, 0x77, 0x6f,
-	0x72, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77,
-	0x65, 0x64, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x12, 0x2e, 0x0a, 0x13, 0x61, 0x75,
-	0x74, 0x6f, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x5f, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72,
-	0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x61, 0x75, 0x74, 0x6f, 0x41, 0x63, 0x63,
-	0x65, 0x70, 0x74, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x61,
-	0x78, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6b, 0x62, 0x70, 0x73, 0x18, 0x0c, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x53, 0x65, 0x6e, 0x64, 0x4b, 0x62, 0x70, 0x73, 0x12, 0x22,
+	0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x12, 0x2e, 0x0a, 0x13,
+	0x6d, 0x61, 0x78, 0x5f, 0x76, 0x61, 0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x6c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x56, 0x61,
+	0x72, 0x69, 0x61, 0x62, 0x6c, 0x65, 0x4c, 0x65, 0x6e, 0x67, 0x74, 0x68, 0x12, 0x23, 0x0a, 0x05,
+	0x68, 0x6f, 0x6f, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x48, 0x6f, 0x6f, 0x6b, 0x73, 0x52, 0x05, 0x68, 0x6f, 0x6f, 0x6b,
+	0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74, 0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x26, 0x0a, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x10, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x50,
+	0x49, 0x52, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x2c, 0x0a, 0x08, 0x64, 0x69, 0x73, 0x63,
+	0x6f, 0x76, 0x65, 0x72, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x52, 0x08, 0x64, 0x69,
+	0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x2d, 0x0a, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
+	0x44, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x52, 0x06, 0x64,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x2d, 0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18,
+	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44,
+	0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x52, 0x06, 0x66, 0x6f,
+	0x6c, 0x64, 0x65, 0x72, 0x12, 0x30, 0x0a, 0x14, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c,
+	0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x12, 0x72, 0x65, 0x63, 0x6f, 0x6e, 0x63, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x74,
+	0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x12, 0x33, 0x0a, 0x16, 0x64, 0x72, 0x69, 0x66, 0x74, 0x5f,
+	0x63, 0x68, 0x65, 0x63, 0x6b, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73,
+	0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x64, 0x72, 0x69, 0x66, 0x74, 0x43, 0x68, 0x65,
+	0x63, 0x6b, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x12, 0x29, 0x0a, 0x07, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x63,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xe4, 0x08, 0x0a, 0x07, 0x4f, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x5f, 0x61, 0x64, 0x64,
+	0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x6c, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73, 0x12, 0x38, 0x0a,
+	0x18, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72,
+	0x79, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x16, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79,
+	0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x73, 0x12, 0x3d, 0x0a, 0x18, 0x67, 0x6c, 0x6f, 0x62, 0x61,
+	0x6c, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x65, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x16, 0x67, 0x6c, 0x6f,
+	0x62, 0x61, 0x6c, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x45, 0x6e, 0x61, 0x62,
+	0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x17, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x15, 0x6c, 0x6f, 0x63, 0x61, 0x6c,
+	0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64,
+	0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x0e, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x5f, 0x65, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x0d, 0x72,
+	0x65, 0x6c, 0x61, 0x79, 0x73, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12,
+	0x37, 0x0a, 0x15, 0x6e, 0x61, 0x74, 0x5f, 0x74, 0x72, 0x61, 0x76, 0x65, 0x72, 0x73, 0x61, 0x6c,
+	0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03,
+	0x52, 0x13, 0x6e, 0x61, 0x74, 0x54, 0x72, 0x61, 0x76, 0x65, 0x72, 0x73, 0x61, 0x6c, 0x45, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x27, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f,
+	0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6b, 0x62, 0x70, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x48,
+	0x04, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x53, 0x65, 0x6e, 0x64, 0x4b, 0x62, 0x70, 0x73, 0x88, 0x01,
+	0x01, 0x12, 0x27, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x5f, 0x6b, 0x62,
+	0x70, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x48, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x52,
+	0x65, 0x63, 0x76, 0x4b, 0x62, 0x70, 0x73, 0x88, 0x01, 0x01, 0x12, 0x38, 0x0a, 0x16, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x5f, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x69, 0x6e,
+	0x5f, 0x6c, 0x61, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x48, 0x06, 0x52, 0x13, 0x6c, 0x69,
+	0x6d, 0x69, 0x74, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x49, 0x6e, 0x4c, 0x61,
+	0x6e, 0x88, 0x01, 0x01, 0x12, 0x3d, 0x0a, 0x18, 0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65,
+	0x70, 0x6f, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x48, 0x07, 0x52, 0x16, 0x75, 0x73, 0x61, 0x67, 0x65, 0x52,
+	0x65, 0x70, 0x6f, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64,
+	0x88, 0x01, 0x01, 0x12, 0x3b, 0x0a, 0x17, 0x63, 0x72, 0x61, 0x73, 0x68, 0x5f, 0x72, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x08, 0x48, 0x08, 0x52, 0x15, 0x63, 0x72, 0x61, 0x73, 0x68, 0x52, 0x65, 0x70,
+	0x6f, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x88, 0x01, 0x01,
+	0x12, 0x3a, 0x0a, 0x17, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x68, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x09, 0x52, 0x14, 0x61, 0x75, 0x74, 0x6f, 0x55, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65,
+	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x48, 0x88, 0x01, 0x01, 0x12, 0x28, 0x0a, 0x0d,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x72, 0x6f, 0x77, 0x73, 0x65, 0x72, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x08, 0x48, 0x0a, 0x52, 0x0c, 0x73, 0x74, 0x61, 0x72, 0x74, 0x42, 0x72, 0x6f, 0x77,
+	0x73, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x2a, 0x0a, 0x11, 0x61, 0x6c, 0x77, 0x61, 0x79, 0x73,
+	0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f, 0x6e, 0x65, 0x74, 0x73, 0x18, 0x0e, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0f, 0x61, 0x6c, 0x77, 0x61, 0x79, 0x73, 0x4c, 0x6f, 0x63, 0x61, 0x6c, 0x4e, 0x65,
+	0x74, 0x73, 0x12, 0x35, 0x0a, 0x14, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6d, 0x61, 0x78, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x0b, 0x52, 0x12, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x4d, 0x61, 0x78, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x03, 0x67, 0x75, 0x69,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
+	0x47, 0x55, 0x49, 0x52, 0x03, 0x67, 0x75, 0x69, 0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x67, 0x6c, 0x6f,
+	0x62, 0x61, 0x6c, 0x5f, 0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x65, 0x6e,
+	0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x1a, 0x0a, 0x18, 0x5f, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x5f,
+	0x64, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65,
+	0x64, 0x42, 0x11, 0x0a, 0x0f, 0x5f, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x73, 0x5f, 0x65, 0x6e, 0x61,
+	0x62, 0x6c, 0x65, 0x64, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x6e, 0x61, 0x74, 0x5f, 0x74, 0x72, 0x61,
+	0x76, 0x65, 0x72, 0x73, 0x61, 0x6c, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x10,
+	0x0a, 0x0e, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6b, 0x62, 0x70, 0x73,
+	0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x5f, 0x6b, 0x62,
+	0x70, 0x73, 0x42, 0x19, 0x0a, 0x17, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x62, 0x61, 0x6e,
+	0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x69, 0x6e, 0x5f, 0x6c, 0x61, 0x6e, 0x42, 0x1b, 0x0a,
+	0x19, 0x5f, 0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x69, 0x6e,
+	0x67, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x65, 0x64, 0x42, 0x1a, 0x0a, 0x18, 0x5f, 0x63,
+	0x72, 0x61, 0x73, 0x68, 0x5f, 0x72, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x69, 0x6e, 0x67, 0x5f, 0x65,
+	0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x42, 0x1a, 0x0a, 0x18, 0x5f, 0x61, 0x75, 0x74, 0x6f, 0x5f,
+	0x75, 0x70, 0x67, 0x72, 0x61, 0x64, 0x65, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
+	0x5f, 0x68, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x62, 0x72, 0x6f,
+	0x77, 0x73, 0x65, 0x72, 0x42, 0x17, 0x0a, 0x15, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x5f, 0x6d, 0x61, 0x78, 0x22, 0xfd, 0x02,
+	0x0a, 0x03, 0x47, 0x55, 0x49, 0x12, 0x1d, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x88, 0x01, 0x01, 0x12, 0x1c, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x5f, 0x74, 0x6c, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x08, 0x48, 0x01, 0x52, 0x06, 0x75, 0x73, 0x65, 0x54, 0x6c, 0x73, 0x88,
+	0x01, 0x01, 0x12, 0x17, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x02, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x88, 0x01, 0x01, 0x12, 0x1f, 0x0a, 0x08, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x03, 0x52,
+	0x08, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x88, 0x01, 0x01, 0x12, 0x19, 0x0a, 0x05,
+	0x74, 0x68, 0x65, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x48, 0x04, 0x52, 0x05, 0x74,
+	0x68, 0x65, 0x6d, 0x65, 0x88, 0x01, 0x01, 0x12, 0x37, 0x0a, 0x15, 0x69, 0x6e, 0x73, 0x65, 0x63,
+	0x75, 0x72, 0x65, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x48, 0x05, 0x52, 0x13, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75,
+	0x72, 0x65, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x63, 0x63, 0x65, 0x73, 0x73, 0x88, 0x01, 0x01,
+	0x12, 0x3c, 0x0a, 0x18, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x73, 0x6b, 0x69,
+	0x70, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x18, 0x07, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x06, 0x52, 0x15, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x53, 0x6b,
+	0x69, 0x70, 0x48, 0x6f, 0x73, 0x74, 0x43, 0x68, 0x65, 0x63, 0x6b, 0x88, 0x01, 0x01, 0x42, 0x0a,
+	0x0a, 0x08, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x42, 0x0a, 0x0a, 0x08, 0x5f, 0x75,
+	0x73, 0x65, 0x5f, 0x74, 0x6c, 0x73, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x42,
+	0x0b, 0x0a, 0x09, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x42, 0x08, 0x0a, 0x06,
+	0x5f, 0x74, 0x68, 0x65, 0x6d, 0x65, 0x42, 0x18, 0x0a, 0x16, 0x5f, 0x69, 0x6e, 0x73, 0x65, 0x63,
+	0x75, 0x72, 0x65, 0x5f, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x73, 0x73,
+	0x42, 0x1b, 0x0a, 0x19, 0x5f, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x73, 0x6b,
+	0x69, 0x70, 0x5f, 0x68, 0x6f, 0x73, 0x74, 0x5f, 0x63, 0x68, 0x65, 0x63, 0x6b, 0x22, 0x8b, 0x02,
+	0x0a, 0x0d, 0x44, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12,
+	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44,
+	0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x58, 0x0a, 0x11,
+	0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x44, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x2e, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x10, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x65,
+	0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x1a, 0x43, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e,
+	0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12,
+	0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8f, 0x02, 0x0a, 0x0d,
+	0x44, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x37, 0x0a,
+	0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x73, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x12, 0x58,
+	0x0a, 0x11, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73, 0x65, 0x6c, 0x65, 0x63,
+	0x74, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x44, 0x65, 0x73, 0x69, 0x72, 0x65, 0x64, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f,
+	0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x1a, 0x43, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x95, 0x01,
+	0x0a, 0x08, 0x44, 0x69, 0x73, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x67, 0x6c,
+	0x6f, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x6c, 0x6f, 0x62, 0x12, 0x1f,
+	0x0a, 0x0b, 0x70, 0x61, 0x74, 0x68, 0x5f, 0x72, 0x65, 0x67, 0x65, 0x78, 0x70, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x61, 0x74, 0x68, 0x52, 0x65, 0x67, 0x65, 0x78, 0x70, 0x12,
+	0x1d, 0x0a, 0x0a, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x12, 0x35,
+	0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x53, 0x79, 0x6e, 0x63, 0x74, 0x68,
+	0x69, 0x6e, 0x67, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x08, 0x73, 0x65, 0x74,
+	0x74, 0x69, 0x6e, 0x67, 0x73, 0x22, 0x4a, 0x0a, 0x08, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x41, 0x50,
+	0x49, 0x12, 0x25, 0x0a, 0x0e, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x5f, 0x61, 0x64, 0x64, 0x72,
+	0x65, 0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6c, 0x69, 0x73, 0x74, 0x65,
+	0x6e, 0x41, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x5f,
+	0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x70, 0x69, 0x4b, 0x65,
+	0x79, 0x22, 0xa8, 0x04, 0x0a, 0x11, 0x53, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x49,
+	0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65,
+	0x73, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73,
+	0x73, 0x12, 0x17, 0x0a, 0x07, 0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63,
+	0x68, 0x65, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x63,
+	0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x63, 0x65, 0x72, 0x74, 0x46, 0x69, 0x6e, 0x67, 0x65,
+	0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x28, 0x0a, 0x10, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x5f, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x26, 0x0a, 0x0f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x66,
+	0x69, 0x6c, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x69, 0x6e, 0x73, 0x65,
+	0x63, 0x75, 0x72, 0x65, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x79,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65,
+	0x53, 0x6b, 0x69, 0x70, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x20, 0x0a, 0x0c, 0x61, 0x70,
+	0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0b,
+	0x61, 0x70, 0x69, 0x5f, 0x6b, 0x65, 0x79, 0x5f, 0x65, 0x6e, 0x76, 0x18, 0x0a, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x61, 0x70, 0x69, 0x4b, 0x65, 0x79, 0x45, 0x6e, 0x76, 0x12, 0x30, 0x0a, 0x14,
+	0x73, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x5f, 0x78, 0x6d, 0x6c, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x73, 0x79, 0x6e, 0x63,
+	0x74, 0x68, 0x69, 0x6e, 0x67, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x58, 0x6d, 0x6c, 0x12, 0x12,
+	0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x0d, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x25, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x53, 0x79, 0x6e, 0x63,
+	0x74, 0x68, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c,
+	0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b,
+	0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xf4, 0x05, 0x0a,
+	0x0d, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x2d,
+	0x0a, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x15,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x50, 0x61,
+	0x74, 0x74, 0x65, 0x72, 0x6e, 0x52, 0x06, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x12, 0x1f, 0x0a,
+	0x0b, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x5f, 0x63, 0x69, 0x64, 0x72, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x0a, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x43, 0x69, 0x64, 0x72, 0x12, 0x37,
+	0x0a, 0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1b, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x73,
+	0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x61, 0x74, 0x68, 0x5f,
+	0x72, 0x6f, 0x6f, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x61, 0x74, 0x68,
+	0x52, 0x6f, 0x6f, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x48, 0x0a, 0x0f, 0x67, 0x61, 0x72, 0x62,
+	0x61, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x61, 0x72, 0x62, 0x61,
+	0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69,
+	0x63, 0x79, 0x52, 0x0e, 0x67, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65,
+	0x63, 0x74, 0x12, 0x58, 0x0a, 0x11, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x5f, 0x73,
+	0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x50, 0x61, 0x74,
+	0x74, 0x65, 0x72, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x6c,
+	0x65, 0x63, 0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x10, 0x69, 0x6e, 0x73, 0x74,
+	0x61, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x12, 0x49, 0x0a, 0x0c,
+	0x70, 0x72, 0x6f, 0x70, 0x61, 0x67, 0x61, 0x74, 0x65, 0x5f, 0x74, 0x6f, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x26, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x44, 0x65, 0x76, 0x69,
+	0x63, 0x65, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x61, 0x67,
+	0x61, 0x74, 0x65, 0x54, 0x6f, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x0b, 0x70, 0x72, 0x6f, 0x70,
+	0x61, 0x67, 0x61, 0x74, 0x65, 0x54, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x63, 0x65,
+	0x6d, 0x65, 0x6e, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x6c, 0x61, 0x63,
+	0x65, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x65, 0x6e, 0x66, 0x6f, 0x72, 0x63, 0x65, 0x12,
+	0x46, 0x0a, 0x12, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x5f, 0x73, 0x63, 0x68,
+	0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x57, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x52, 0x11, 0x62, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74, 0x68, 0x53,
+	0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x74, 0x6c, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x74, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x5f, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x43,
+	0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x3e, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x70, 0x61, 0x67, 0x61, 0x74, 0x65,
+	0x54, 0x6f, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x22, 0xe5, 0x01, 0x0a, 0x0f, 0x42, 0x61, 0x6e, 0x64, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x18, 0x0a, 0x07, 0x77, 0x65, 0x65, 0x6b, 0x64,
+	0x61, 0x79, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x77, 0x65, 0x65, 0x6b, 0x64, 0x61,
+	0x79, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x74, 0x69, 0x6d,
+	0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x27, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x65, 0x6e,
+	0x64, 0x5f, 0x6b, 0x62, 0x70, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0b,
+	0x6d, 0x61, 0x78, 0x53, 0x65, 0x6e, 0x64, 0x4b, 0x62, 0x70, 0x73, 0x88, 0x01, 0x01, 0x12, 0x27,
 	0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x5f, 0x6b, 0x62, 0x70, 0x73, 0x18,
-	0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x63, 0x76, 0x4b, 0x62,
-	0x70, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x5f, 0x6b, 0x69, 0x62, 0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6d, 0x61, 0x78,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4b, 0x69, 0x62, 0x12, 0x27, 0x0a, 0x0f, 0x6e, 0x75,
-	0x6d, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x13, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x0e, 0x6e, 0x75, 0x6d, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x73, 0x22, 0xa3, 0x0b, 0x0a, 0x13, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a, 0x05, 0x6c,
-	0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61, 0x62, 0x65,
-	0x6c, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x46, 0x6f, 0x6c,
-	0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2a, 0x0a,
-	0x11, 0x72, 0x65, 0x73, 0x63, 0x61, 0x6e, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c,
-	0x5f, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x63, 0x61, 0x6e,
-	0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x12, 0x2e, 0x0a, 0x13, 0x66, 0x73, 0x5f,
-	0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x5f, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64,
-	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x66, 0x73, 0x57, 0x61, 0x74, 0x63, 0x68, 0x65,
-	0x72, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x2b, 0x0a, 0x12, 0x66, 0x73, 0x5f,
-	0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x5f, 0x73, 0x18,
-	0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x66, 0x73, 0x57, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72,
-	0x44, 0x65, 0x6c, 0x61, 0x79, 0x53, 0x12, 0x2d, 0x0a, 0x12, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65,
-	0x5f, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x11, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x50, 0x65, 0x72, 0x6d, 0x69, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6e, 0x6f, 0x5f, 0x61, 0x75, 0x74, 0x6f,
-	0x5f, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x0f, 0x6e, 0x6f, 0x41, 0x75, 0x74, 0x6f, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a,
-	0x65, 0x12, 0x30, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x64, 0x69, 0x73, 0x6b, 0x5f, 0x66, 0x72,
-	0x65, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x2e, 0x53, 0x69, 0x7a, 0x65, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x44, 0x69, 0x73, 0x6b, 0x46,
-	0x72, 0x65, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x73, 0x18, 0x0e,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x73, 0x12, 0x33, 0x0a,
-	0x16, 0x70, 0x75, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x65, 0x6e, 0x64,
-	0x69, 0x6e, 0x67, 0x5f, 0x6b, 0x69, 0x62, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x70,
-	0x75, 0x6c, 0x6c, 0x65, 0x72, 0x4d, 0x61, 0x78, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e, 0x67, 0x4b,
-	0x69, 0x62, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x73, 0x18, 0x10, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x07, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x73, 0x12, 0x27, 0x0a, 0x05,
-	0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e, 0x63, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x05,
-	0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x5f,
-	0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x69, 0x67,
-	0x6e, 0x6f, 0x72, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x18, 0x73, 0x63,
-	0x61, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x69, 0x6e, 0x74, 0x65,
-	0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x15, 0x73, 0x63,
-	0x61, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76,
-	0x61, 0x6c, 0x53, 0x12, 0x24, 0x0a, 0x0e, 0x70, 0x75, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x70, 0x61,
-	0x75, 0x73, 0x65, 0x5f, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x70, 0x75, 0x6c,
-	0x6c, 0x65, 0x72, 0x50, 0x61, 0x75, 0x73, 0x65, 0x53, 0x12, 0x23, 0x0a, 0x0d, 0x6d, 0x61, 0x78,
-	0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x73, 0x18, 0x15, 0x20, 0x01, 0x28, 0x05,
-	0x52, 0x0c, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x73, 0x12, 0x30,
-	0x0a, 0x14, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x70, 0x61, 0x72, 0x73, 0x65,
-	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x64, 0x69,
-	0x73, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x70, 0x61, 0x72, 0x73, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x73,
-	0x12, 0x30, 0x0a, 0x14, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x74, 0x65, 0x6d, 0x70,
-	0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12,
-	0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x54, 0x65, 0x6d, 0x70, 0x49, 0x6e, 0x64, 0x65, 0x78,
-	0x65, 0x73, 0x12, 0x35, 0x0a, 0x17, 0x77, 0x65, 0x61, 0x6b, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x5f,
-	0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x63, 0x74, 0x18, 0x19, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x14, 0x77, 0x65, 0x61, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x54, 0x68, 0x72,
-	0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x50, 0x63, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x61, 0x72,
-	0x6b, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x6d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3b, 0x0a, 0x1a, 0x63, 0x6f,
-	0x70, 0x79, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x5f, 0x66, 0x72, 0x6f,
-	0x6d, 0x5f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x17,
-	0x63, 0x6f, 0x70, 0x79, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x46, 0x72, 0x6f,
-	0x6d, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x11, 0x6d, 0x6f, 0x64, 0x5f, 0x74,
-	0x69, 0x6d, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x18, 0x1c, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x0e, 0x6d, 0x6f, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x57, 0x69, 0x6e, 0x64, 0x6f,
-	0x77, 0x53, 0x12, 0x32, 0x0a, 0x15, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72,
-	0x72, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x18, 0x1d, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
-	0x57, 0x72, 0x69, 0x74, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c,
-	0x65, 0x5f, 0x66, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x64,
-	0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x46, 0x73, 0x79, 0x6e, 0x63, 0x12, 0x40, 0x0a, 0x10, 0x62,
-	0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x70, 0x75, 0x6c, 0x6c, 0x5f, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18,
-	0x1f, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52, 0x0e, 0x62,
-	0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x43, 0x0a,
-	0x11, 0x63, 0x6f, 0x70, 0x79, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6d, 0x65, 0x74, 0x68,
-	0x6f, 0x64, 0x18, 0x20, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x68, 0x6f,
-	0x64, 0x52, 0x0f, 0x63, 0x6f, 0x70, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x68,
-	0x6f, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x61, 0x73, 0x65, 0x5f, 0x73, 0x65, 0x6e, 0x73, 0x69,
-	0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x73, 0x18, 0x21, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x63,
-	0x61, 0x73, 0x65, 0x53, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x46, 0x73, 0x12, 0x29,
-	0x0a, 0x10, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6a, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x18, 0x22, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77,
-	0x4a, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x79, 0x6e,
-	0x63, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x18, 0x23, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x0d, 0x73, 0x79, 0x6e, 0x63, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70,
-	0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68,
-	0x69, 0x70, 0x18, 0x24, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x65, 0x6e, 0x64, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x79, 0x6e, 0x63, 0x5f,
-	0x78, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x25, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73, 0x79,
-	0x6e, 0x63, 0x58, 0x61, 0x74, 0x74, 0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x6e, 0x64,
-	0x5f, 0x78, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x26, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a, 0x73,
-	0x65, 0x6e, 0x64, 0x58, 0x61, 0x74, 0x74, 0x72, 0x73, 0x22, 0x30, 0x0a, 0x04, 0x53, 0x69, 0x7a,
-	0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01,
-	0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0x8e, 0x01, 0x0a, 0x11,
-	0x47, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x1e, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x5f, 0x65, 0x76, 0x65, 0x72, 0x79, 0x5f, 0x73,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x75, 0x6e, 0x45, 0x76, 0x65, 0x72, 0x79,
-	0x53, 0x12, 0x2e, 0x0a, 0x13, 0x75, 0x6e, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x64, 0x65, 0x76, 0x69,
-	0x63, 0x65, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x11,
-	0x75, 0x6e, 0x73, 0x65, 0x65, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x44, 0x61, 0x79,
-	0x73, 0x12, 0x29, 0x0a, 0x10, 0x75, 0x6e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x66, 0x6f,
-	0x6c, 0x64, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x75, 0x6e, 0x73,
-	0x68, 0x61, 0x72, 0x65, 0x64, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x2a, 0x56, 0x0a, 0x0a,
-	0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45,
-	0x4e, 0x44, 0x5f, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09,
-	0x53, 0x45, 0x4e, 0x44, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x52,
-	0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x5f, 0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x02, 0x12, 0x15, 0x0a,
-	0x11, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x5f, 0x45, 0x4e, 0x43, 0x52, 0x59, 0x50, 0x54,
-	0x45, 0x44, 0x10, 0x03, 0x2a, 0x90, 0x01, 0x0a, 0x09, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64,
-	0x65, 0x72, 0x12, 0x0f, 0x0a, 0x0b, 0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x52, 0x41, 0x4e, 0x44, 0x4f,
-	0x4d, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x41, 0x4c, 0x50, 0x48,
-	0x41, 0x42, 0x45, 0x54, 0x49, 0x43, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x50, 0x55, 0x4c, 0x4c,
-	0x5f, 0x53, 0x4d, 0x41, 0x4c, 0x4c, 0x45, 0x53, 0x54, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10,
-	0x02, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x4c, 0x41, 0x52, 0x47, 0x45, 0x53,
-	0x54, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x55, 0x4c,
-	0x4c, 0x5f, 0x4f, 0x4c, 0x44, 0x45, 0x53, 0x54, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x04,
-	0x12, 0x15, 0x0a, 0x11, 0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x4e, 0x45, 0x57, 0x45, 0x53, 0x54, 0x5f,
-	0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x05, 0x2a, 0x4d, 0x0a, 0x0e, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
-	0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c, 0x4f,
-	0x43, 0x4b, 0x53, 0x5f, 0x53, 0x54, 0x41, 0x4e, 0x44, 0x41, 0x52, 0x44, 0x10, 0x00, 0x12, 0x11,
-	0x0a, 0x0d, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x53, 0x5f, 0x52, 0x41, 0x4e, 0x44, 0x4f, 0x4d, 0x10,
-	0x01, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x53, 0x5f, 0x49, 0x4e, 0x5f, 0x4f,
-	0x52, 0x44, 0x45, 0x52, 0x10, 0x02, 0x2a, 0xbe, 0x01, 0x0a, 0x0f, 0x43, 0x6f, 0x70, 0x79, 0x52,
-	0x61, 0x6e, 0x67, 0x65, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x4f,
-	0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x53, 0x54, 0x41, 0x4e, 0x44, 0x41, 0x52,
-	0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47,
-	0x45, 0x5f, 0x49, 0x4f, 0x43, 0x54, 0x4c, 0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x43, 0x4f, 0x50,
-	0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x46, 0x49, 0x4c,
-	0x45, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x43, 0x4f, 0x50,
-	0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x53, 0x45, 0x4e, 0x44, 0x5f, 0x46, 0x49, 0x4c,
-	0x45, 0x10, 0x03, 0x12, 0x20, 0x0a, 0x1c, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47,
-	0x45, 0x5f, 0x44, 0x55, 0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x45, 0x5f, 0x45, 0x58, 0x54, 0x45,
-	0x4e, 0x54, 0x53, 0x10, 0x04, 0x12, 0x20, 0x0a, 0x1c, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41,
-	0x4e, 0x47, 0x45, 0x5f, 0x41, 0x4c, 0x4c, 0x5f, 0x57, 0x49, 0x54, 0x48, 0x5f, 0x46, 0x41, 0x4c,
-	0x4c, 0x42, 0x41, 0x43, 0x4b, 0x10, 0x05, 0x42, 0x2f, 0x5a, 0x2d, 0x6b, 0x61, 0x73, 0x74, 0x65,
-	0x6c, 0x6f, 0x2e, 0x64, 0x65, 0x76, 0x2f, 0x73, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67,
-	0x2d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x64, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61,
-	0x6c, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x63, 0x76,
+	0x4b, 0x62, 0x70, 0x73, 0x88, 0x01, 0x01, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6d, 0x61, 0x78, 0x5f,
+	0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6b, 0x62, 0x70, 0x73, 0x42, 0x10, 0x0a, 0x0e, 0x5f, 0x6d, 0x61,
+	0x78, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x5f, 0x6b, 0x62, 0x70, 0x73, 0x22, 0xe6, 0x01, 0x0a, 0x0d,
+	0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x12, 0x0e, 0x0a,
+	0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x37, 0x0a,
+	0x08, 0x73, 0x65, 0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1b, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x73, 0x65,
+	0x74, 0x74, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x42, 0x0a, 0x10, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x5f, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x0f, 0x63, 0x72, 0x65, 0x61, 0x74,
+	0x65, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x48, 0x0a, 0x0f, 0x67, 0x61,
+	0x72, 0x62, 0x61, 0x67, 0x65, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x61, 0x72,
+	0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x52, 0x0e, 0x67, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c,
+	0x6c, 0x65, 0x63, 0x74, 0x22, 0x7b, 0x0a, 0x0f, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x44, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x15, 0x0a, 0x03, 0x75, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x03, 0x75, 0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x15,
+	0x0a, 0x03, 0x67, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x03, 0x67,
+	0x69, 0x64, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x61, 0x72,
+	0x6b, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x6d, 0x61, 0x72, 0x6b, 0x65,
+	0x72, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x75, 0x69, 0x64, 0x42, 0x06, 0x0a, 0x04, 0x5f, 0x67, 0x69,
+	0x64, 0x22, 0xde, 0x01, 0x0a, 0x05, 0x48, 0x6f, 0x6f, 0x6b, 0x73, 0x12, 0x29, 0x0a, 0x09, 0x6f,
+	0x6e, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c,
+	0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x48, 0x6f, 0x6f, 0x6b, 0x52, 0x08, 0x6f, 0x6e,
+	0x41, 0x63, 0x63, 0x65, 0x70, 0x74, 0x12, 0x25, 0x0a, 0x07, 0x6f, 0x6e, 0x5f, 0x64, 0x65, 0x6e,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x48, 0x6f, 0x6f, 0x6b, 0x52, 0x06, 0x6f, 0x6e, 0x44, 0x65, 0x6e, 0x79, 0x12, 0x2e, 0x0a,
+	0x0c, 0x6f, 0x6e, 0x5f, 0x67, 0x63, 0x5f, 0x72, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x48, 0x6f, 0x6f,
+	0x6b, 0x52, 0x0a, 0x6f, 0x6e, 0x47, 0x63, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x65, 0x12, 0x25, 0x0a,
+	0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x74, 0x12, 0x2c, 0x0a, 0x0b, 0x6f, 0x6e, 0x5f, 0x67, 0x63, 0x5f, 0x61, 0x6c,
+	0x65, 0x72, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x48, 0x6f, 0x6f, 0x6b, 0x52, 0x09, 0x6f, 0x6e, 0x47, 0x63, 0x41, 0x6c, 0x65,
+	0x72, 0x74, 0x22, 0x77, 0x0a, 0x04, 0x48, 0x6f, 0x6f, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d,
+	0x6d, 0x61, 0x6e, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74, 0x5f,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x69, 0x6d, 0x65, 0x6f, 0x75, 0x74,
+	0x53, 0x12, 0x38, 0x0a, 0x0a, 0x6f, 0x6e, 0x5f, 0x66, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x48,
+	0x6f, 0x6f, 0x6b, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79,
+	0x52, 0x09, 0x6f, 0x6e, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x22, 0xa7, 0x02, 0x0a, 0x13,
+	0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x61, 0x64, 0x64, 0x72, 0x65, 0x73, 0x73, 0x65,
+	0x73, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x6e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0f, 0x61, 0x6c, 0x6c,
+	0x6f, 0x77, 0x65, 0x64, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x73, 0x12, 0x2e, 0x0a, 0x13,
+	0x61, 0x75, 0x74, 0x6f, 0x5f, 0x61, 0x63, 0x63, 0x65, 0x70, 0x74, 0x5f, 0x66, 0x6f, 0x6c, 0x64,
+	0x65, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x61, 0x75, 0x74, 0x6f, 0x41,
+	0x63, 0x63, 0x65, 0x70, 0x74, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x0d,
+	0x6d, 0x61, 0x78, 0x5f, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6b, 0x62, 0x70, 0x73, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x53, 0x65, 0x6e, 0x64, 0x4b, 0x62, 0x70, 0x73,
+	0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x63, 0x76, 0x5f, 0x6b, 0x62, 0x70,
+	0x73, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x63, 0x76,
+	0x4b, 0x62, 0x70, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x5f, 0x6b, 0x69, 0x62, 0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6d,
+	0x61, 0x78, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4b, 0x69, 0x62, 0x12, 0x27, 0x0a, 0x0f,
+	0x6e, 0x75, 0x6d, 0x5f, 0x63, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x6e, 0x75, 0x6d, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0xa3, 0x0b, 0x0a, 0x13, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14, 0x0a,
+	0x05, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6c, 0x61,
+	0x62, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x74, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x70, 0x61, 0x74, 0x68, 0x12, 0x26, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x46,
+	0x6f, 0x6c, 0x64, 0x65, 0x72, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12,
+	0x2a, 0x0a, 0x11, 0x72, 0x65, 0x73, 0x63, 0x61, 0x6e, 0x5f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x76,
+	0x61, 0x6c, 0x5f, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x72, 0x65, 0x73, 0x63,
+	0x61, 0x6e, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x53, 0x12, 0x2e, 0x0a, 0x13, 0x66,
+	0x73, 0x5f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x5f, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c,
+	0x65, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x66, 0x73, 0x57, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x72, 0x44, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x2b, 0x0a, 0x12, 0x66,
+	0x73, 0x5f, 0x77, 0x61, 0x74, 0x63, 0x68, 0x65, 0x72, 0x5f, 0x64, 0x65, 0x6c, 0x61, 0x79, 0x5f,
+	0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0f, 0x66, 0x73, 0x57, 0x61, 0x74, 0x63, 0x68,
+	0x65, 0x72, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x53, 0x12, 0x2d, 0x0a, 0x12, 0x69, 0x67, 0x6e, 0x6f,
+	0x72, 0x65, 0x5f, 0x70, 0x65, 0x72, 0x6d, 0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x11, 0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x50, 0x65, 0x72, 0x6d,
+	0x69, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x6e, 0x6f, 0x5f, 0x61, 0x75,
+	0x74, 0x6f, 0x5f, 0x6e, 0x6f, 0x72, 0x6d, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0f, 0x6e, 0x6f, 0x41, 0x75, 0x74, 0x6f, 0x4e, 0x6f, 0x72, 0x6d, 0x61, 0x6c,
+	0x69, 0x7a, 0x65, 0x12, 0x30, 0x0a, 0x0d, 0x6d, 0x69, 0x6e, 0x5f, 0x64, 0x69, 0x73, 0x6b, 0x5f,
+	0x66, 0x72, 0x65, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x53, 0x69, 0x7a, 0x65, 0x52, 0x0b, 0x6d, 0x69, 0x6e, 0x44, 0x69, 0x73,
+	0x6b, 0x46, 0x72, 0x65, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x73,
+	0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x63, 0x6f, 0x70, 0x69, 0x65, 0x72, 0x73, 0x12,
+	0x33, 0x0a, 0x16, 0x70, 0x75, 0x6c, 0x6c, 0x65, 0x72, 0x5f, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x65,
+	0x6e, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x6b, 0x69, 0x62, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x13, 0x70, 0x75, 0x6c, 0x6c, 0x65, 0x72, 0x4d, 0x61, 0x78, 0x50, 0x65, 0x6e, 0x64, 0x69, 0x6e,
+	0x67, 0x4b, 0x69, 0x62, 0x12, 0x18, 0x0a, 0x07, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x73, 0x18,
+	0x10, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x68, 0x61, 0x73, 0x68, 0x65, 0x72, 0x73, 0x12, 0x27,
+	0x0a, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11, 0x2e,
+	0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72,
+	0x52, 0x05, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x0d, 0x69, 0x67, 0x6e, 0x6f, 0x72,
+	0x65, 0x5f, 0x64, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
+	0x69, 0x67, 0x6e, 0x6f, 0x72, 0x65, 0x44, 0x65, 0x6c, 0x65, 0x74, 0x65, 0x12, 0x37, 0x0a, 0x18,
+	0x73, 0x63, 0x61, 0x6e, 0x5f, 0x70, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x5f, 0x69, 0x6e,
+	0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x5f, 0x73, 0x18, 0x13, 0x20, 0x01, 0x28, 0x05, 0x52, 0x15,
+	0x73, 0x63, 0x61, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x49, 0x6e, 0x74, 0x65,
+	0x72, 0x76, 0x61, 0x6c, 0x53, 0x12, 0x24, 0x0a, 0x0e, 0x70, 0x75, 0x6c, 0x6c, 0x65, 0x72, 0x5f,
+	0x70, 0x61, 0x75, 0x73, 0x65, 0x5f, 0x73, 0x18, 0x14, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x70,
+	0x75, 0x6c, 0x6c, 0x65, 0x72, 0x50, 0x61, 0x75, 0x73, 0x65, 0x53, 0x12, 0x23, 0x0a, 0x0d, 0x6d,
+	0x61, 0x78, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x73, 0x18, 0x15, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x66, 0x6c, 0x69, 0x63, 0x74, 0x73,
+	0x12, 0x30, 0x0a, 0x14, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x73, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x16, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12,
+	0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x70, 0x61, 0x72, 0x73, 0x65, 0x46, 0x69, 0x6c,
+	0x65, 0x73, 0x12, 0x30, 0x0a, 0x14, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x74, 0x65,
+	0x6d, 0x70, 0x5f, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x65, 0x73, 0x18, 0x17, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x12, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x54, 0x65, 0x6d, 0x70, 0x49, 0x6e, 0x64,
+	0x65, 0x78, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x17, 0x77, 0x65, 0x61, 0x6b, 0x5f, 0x68, 0x61, 0x73,
+	0x68, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x5f, 0x70, 0x63, 0x74, 0x18,
+	0x19, 0x20, 0x01, 0x28, 0x05, 0x52, 0x14, 0x77, 0x65, 0x61, 0x6b, 0x48, 0x61, 0x73, 0x68, 0x54,
+	0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x50, 0x63, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d,
+	0x61, 0x72, 0x6b, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x6d, 0x61, 0x72, 0x6b, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3b, 0x0a, 0x1a,
+	0x63, 0x6f, 0x70, 0x79, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x5f, 0x66,
+	0x72, 0x6f, 0x6d, 0x5f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x17, 0x63, 0x6f, 0x70, 0x79, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x46,
+	0x72, 0x6f, 0x6d, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x11, 0x6d, 0x6f, 0x64,
+	0x5f, 0x74, 0x69, 0x6d, 0x65, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x73, 0x18, 0x1c,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x6d, 0x6f, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x57, 0x69, 0x6e,
+	0x64, 0x6f, 0x77, 0x53, 0x12, 0x32, 0x0a, 0x15, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6f, 0x6e, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x77, 0x72, 0x69, 0x74, 0x65, 0x73, 0x18, 0x1d, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x63, 0x75, 0x72, 0x72, 0x65,
+	0x6e, 0x74, 0x57, 0x72, 0x69, 0x74, 0x65, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x5f, 0x66, 0x73, 0x79, 0x6e, 0x63, 0x18, 0x1e, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0c, 0x64, 0x69, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x46, 0x73, 0x79, 0x6e, 0x63, 0x12, 0x40, 0x0a,
+	0x10, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x70, 0x75, 0x6c, 0x6c, 0x5f, 0x6f, 0x72, 0x64, 0x65,
+	0x72, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x52,
+	0x0e, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12,
+	0x43, 0x0a, 0x11, 0x63, 0x6f, 0x70, 0x79, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x5f, 0x6d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x18, 0x20, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x52, 0x0f, 0x63, 0x6f, 0x70, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x12, 0x2a, 0x0a, 0x11, 0x63, 0x61, 0x73, 0x65, 0x5f, 0x73, 0x65, 0x6e,
+	0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x5f, 0x66, 0x73, 0x18, 0x21, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0f, 0x63, 0x61, 0x73, 0x65, 0x53, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x69, 0x76, 0x65, 0x46, 0x73,
+	0x12, 0x29, 0x0a, 0x10, 0x66, 0x6f, 0x6c, 0x6c, 0x6f, 0x77, 0x5f, 0x6a, 0x75, 0x6e, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x73, 0x18, 0x22, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x66, 0x6f, 0x6c, 0x6c,
+	0x6f, 0x77, 0x4a, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73,
+	0x79, 0x6e, 0x63, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x18, 0x23, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x79, 0x6e, 0x63, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68,
+	0x69, 0x70, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x6e, 0x64, 0x5f, 0x6f, 0x77, 0x6e, 0x65, 0x72,
+	0x73, 0x68, 0x69, 0x70, 0x18, 0x24, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x65, 0x6e, 0x64,
+	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x79, 0x6e,
+	0x63, 0x5f, 0x78, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x25, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0a,
+	0x73, 0x79, 0x6e, 0x63, 0x58, 0x61, 0x74, 0x74, 0x72, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65,
+	0x6e, 0x64, 0x5f, 0x78, 0x61, 0x74, 0x74, 0x72, 0x73, 0x18, 0x26, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x0a, 0x73, 0x65, 0x6e, 0x64, 0x58, 0x61, 0x74, 0x74, 0x72, 0x73, 0x22, 0x30, 0x0a, 0x04, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x6e, 0x69,
+	0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x75, 0x6e, 0x69, 0x74, 0x22, 0xd7, 0x05,
+	0x0a, 0x11, 0x47, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x5f, 0x65, 0x76, 0x65, 0x72, 0x79,
+	0x5f, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x72, 0x75, 0x6e, 0x45, 0x76, 0x65,
+	0x72, 0x79, 0x53, 0x12, 0x2e, 0x0a, 0x13, 0x75, 0x6e, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x11, 0x75, 0x6e, 0x73, 0x65, 0x65, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x44,
+	0x61, 0x79, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x75, 0x6e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f,
+	0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f, 0x75,
+	0x6e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x12, 0x21,
+	0x0a, 0x0c, 0x6f, 0x6e, 0x6c, 0x79, 0x5f, 0x6d, 0x61, 0x6e, 0x61, 0x67, 0x65, 0x64, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x6f, 0x6e, 0x6c, 0x79, 0x4d, 0x61, 0x6e, 0x61, 0x67, 0x65,
+	0x64, 0x12, 0x31, 0x0a, 0x15, 0x6e, 0x65, 0x76, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x5f,
+	0x67, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x12, 0x6e, 0x65, 0x76, 0x65, 0x72, 0x53, 0x65, 0x65, 0x6e, 0x47, 0x72, 0x61, 0x63, 0x65,
+	0x44, 0x61, 0x79, 0x73, 0x12, 0x2f, 0x0a, 0x14, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x6d, 0x6f,
+	0x76, 0x61, 0x6c, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x72, 0x75, 0x6e, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x11, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x73, 0x50,
+	0x65, 0x72, 0x52, 0x75, 0x6e, 0x12, 0x30, 0x0a, 0x14, 0x6d, 0x61, 0x78, 0x5f, 0x72, 0x65, 0x6d,
+	0x6f, 0x76, 0x61, 0x6c, 0x5f, 0x66, 0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x01, 0x52, 0x12, 0x6d, 0x61, 0x78, 0x52, 0x65, 0x6d, 0x6f, 0x76, 0x61, 0x6c, 0x46,
+	0x72, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x74, 0x65,
+	0x63, 0x74, 0x18, 0x08, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x74, 0x65, 0x63,
+	0x74, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x64, 0x69, 0x73, 0x70, 0x6f, 0x73,
+	0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x64, 0x61, 0x74,
+	0x61, 0x44, 0x69, 0x73, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x27, 0x0a, 0x0f,
+	0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e, 0x65, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x71, 0x75, 0x61, 0x72, 0x61, 0x6e, 0x74, 0x69, 0x6e,
+	0x65, 0x44, 0x61, 0x79, 0x73, 0x12, 0x2a, 0x0a, 0x11, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x5f, 0x73,
+	0x68, 0x61, 0x72, 0x65, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73, 0x44, 0x61, 0x79,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x08, 0x73, 0x63, 0x68, 0x65, 0x64, 0x75, 0x6c, 0x65, 0x12, 0x1a, 0x0a,
+	0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x74, 0x69, 0x6d, 0x65, 0x7a, 0x6f, 0x6e, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x6a, 0x69, 0x74,
+	0x74, 0x65, 0x72, 0x5f, 0x73, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6a, 0x69, 0x74,
+	0x74, 0x65, 0x72, 0x53, 0x12, 0x5c, 0x0a, 0x11, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65,
+	0x5f, 0x73, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x2f, 0x2e, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x61, 0x72, 0x62, 0x61, 0x67, 0x65,
+	0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x49, 0x6e, 0x73, 0x74, 0x61,
+	0x6e, 0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x10, 0x69, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x65, 0x6c, 0x65, 0x63, 0x74,
+	0x6f, 0x72, 0x1a, 0x43, 0x0a, 0x15, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x53, 0x65,
+	0x6c, 0x65, 0x63, 0x74, 0x6f, 0x72, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61,
+	0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x9f, 0x03, 0x0a, 0x17, 0x47, 0x61, 0x72, 0x62,
+	0x61, 0x67, 0x65, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c,
+	0x69, 0x63, 0x79, 0x12, 0x33, 0x0a, 0x13, 0x75, 0x6e, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x64, 0x65,
+	0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05,
+	0x48, 0x00, 0x52, 0x11, 0x75, 0x6e, 0x73, 0x65, 0x65, 0x6e, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+	0x73, 0x44, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x15, 0x6e, 0x65, 0x76, 0x65,
+	0x72, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x67, 0x72, 0x61, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x79,
+	0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x48, 0x01, 0x52, 0x12, 0x6e, 0x65, 0x76, 0x65, 0x72,
+	0x53, 0x65, 0x65, 0x6e, 0x47, 0x72, 0x61, 0x63, 0x65, 0x44, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01,
+	0x12, 0x2e, 0x0a, 0x10, 0x75, 0x6e, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x66, 0x6f, 0x6c,
+	0x64, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x48, 0x02, 0x52, 0x0f, 0x75, 0x6e,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x88, 0x01, 0x01,
+	0x12, 0x14, 0x0a, 0x05, 0x6e, 0x65, 0x76, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x05, 0x6e, 0x65, 0x76, 0x65, 0x72, 0x12, 0x2e, 0x0a, 0x10, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x64,
+	0x69, 0x73, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x48, 0x03, 0x52, 0x0f, 0x64, 0x61, 0x74, 0x61, 0x44, 0x69, 0x73, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x88, 0x01, 0x01, 0x12, 0x2f, 0x0a, 0x11, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x5f,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x05, 0x48, 0x04, 0x52, 0x0f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x53, 0x68, 0x61, 0x72, 0x65, 0x73,
+	0x44, 0x61, 0x79, 0x73, 0x88, 0x01, 0x01, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x75, 0x6e, 0x73, 0x65,
+	0x65, 0x6e, 0x5f, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x42,
+	0x18, 0x0a, 0x16, 0x5f, 0x6e, 0x65, 0x76, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x65, 0x6e, 0x5f, 0x67,
+	0x72, 0x61, 0x63, 0x65, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x42, 0x13, 0x0a, 0x11, 0x5f, 0x75, 0x6e,
+	0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x66, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x73, 0x42, 0x13,
+	0x0a, 0x11, 0x5f, 0x64, 0x61, 0x74, 0x61, 0x5f, 0x64, 0x69, 0x73, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x42, 0x14, 0x0a, 0x12, 0x5f, 0x73, 0x74, 0x61, 0x6c, 0x65, 0x5f, 0x73, 0x68,
+	0x61, 0x72, 0x65, 0x73, 0x5f, 0x64, 0x61, 0x79, 0x73, 0x2a, 0x3a, 0x0a, 0x11, 0x48, 0x6f, 0x6f,
+	0x6b, 0x46, 0x61, 0x69, 0x6c, 0x75, 0x72, 0x65, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x0f,
+	0x0a, 0x0b, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x5f, 0x4c, 0x4f, 0x47, 0x10, 0x00, 0x12,
+	0x14, 0x0a, 0x10, 0x46, 0x41, 0x49, 0x4c, 0x55, 0x52, 0x45, 0x5f, 0x52, 0x4f, 0x4c, 0x4c, 0x42,
+	0x41, 0x43, 0x4b, 0x10, 0x01, 0x2a, 0x56, 0x0a, 0x0a, 0x46, 0x6f, 0x6c, 0x64, 0x65, 0x72, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x45, 0x4e, 0x44, 0x5f, 0x52, 0x45, 0x43, 0x45,
+	0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x53, 0x45, 0x4e, 0x44, 0x5f, 0x4f, 0x4e,
+	0x4c, 0x59, 0x10, 0x01, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56, 0x45, 0x5f,
+	0x4f, 0x4e, 0x4c, 0x59, 0x10, 0x02, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45, 0x43, 0x45, 0x49, 0x56,
+	0x45, 0x5f, 0x45, 0x4e, 0x43, 0x52, 0x59, 0x50, 0x54, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x90, 0x01,
+	0x0a, 0x09, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x0f, 0x0a, 0x0b, 0x50,
+	0x55, 0x4c, 0x4c, 0x5f, 0x52, 0x41, 0x4e, 0x44, 0x4f, 0x4d, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f,
+	0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x41, 0x4c, 0x50, 0x48, 0x41, 0x42, 0x45, 0x54, 0x49, 0x43, 0x10,
+	0x01, 0x12, 0x17, 0x0a, 0x13, 0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x53, 0x4d, 0x41, 0x4c, 0x4c, 0x45,
+	0x53, 0x54, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x50, 0x55,
+	0x4c, 0x4c, 0x5f, 0x4c, 0x41, 0x52, 0x47, 0x45, 0x53, 0x54, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54,
+	0x10, 0x03, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x55, 0x4c, 0x4c, 0x5f, 0x4f, 0x4c, 0x44, 0x45, 0x53,
+	0x54, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x04, 0x12, 0x15, 0x0a, 0x11, 0x50, 0x55, 0x4c,
+	0x4c, 0x5f, 0x4e, 0x45, 0x57, 0x45, 0x53, 0x54, 0x5f, 0x46, 0x49, 0x52, 0x53, 0x54, 0x10, 0x05,
+	0x2a, 0x4d, 0x0a, 0x0e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x75, 0x6c, 0x6c, 0x4f, 0x72, 0x64,
+	0x65, 0x72, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x53, 0x5f, 0x53, 0x54, 0x41,
+	0x4e, 0x44, 0x41, 0x52, 0x44, 0x10, 0x00, 0x12, 0x11, 0x0a, 0x0d, 0x42, 0x4c, 0x4f, 0x43, 0x4b,
+	0x53, 0x5f, 0x52, 0x41, 0x4e, 0x44, 0x4f, 0x4d, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c,
+	0x4f, 0x43, 0x4b, 0x53, 0x5f, 0x49, 0x4e, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x10, 0x02, 0x2a,
+	0xbe, 0x01, 0x0a, 0x0f, 0x43, 0x6f, 0x70, 0x79, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x4d, 0x65, 0x74,
+	0x68, 0x6f, 0x64, 0x12, 0x17, 0x0a, 0x13, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47,
+	0x45, 0x5f, 0x53, 0x54, 0x41, 0x4e, 0x44, 0x41, 0x52, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10,
+	0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x49, 0x4f, 0x43, 0x54, 0x4c,
+	0x10, 0x01, 0x12, 0x1e, 0x0a, 0x1a, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45,
+	0x5f, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45,
+	0x10, 0x02, 0x12, 0x18, 0x0a, 0x14, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45,
+	0x5f, 0x53, 0x45, 0x4e, 0x44, 0x5f, 0x46, 0x49, 0x4c, 0x45, 0x10, 0x03, 0x12, 0x20, 0x0a, 0x1c,
+	0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x44, 0x55, 0x50, 0x4c, 0x49,
+	0x43, 0x41, 0x54, 0x45, 0x5f, 0x45, 0x58, 0x54, 0x45, 0x4e, 0x54, 0x53, 0x10, 0x04, 0x12, 0x20,
+	0x0a, 0x1c, 0x43, 0x4f, 0x50, 0x59, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x41, 0x4c, 0x4c,
+	0x5f, 0x57, 0x49, 0x54, 0x48, 0x5f, 0x46, 0x41, 0x4c, 0x4c, 0x42, 0x41, 0x43, 0x4b, 0x10, 0x05,
+	0x42, 0x2f, 0x5a, 0x2d, 0x6b, 0x61, 0x73, 0x74, 0x65, 0x6c, 0x6f, 0x2e, 0x64, 0x65, 0x76, 0x2f,
+	0x73, 0x79, 0x6e, 0x63, 0x74, 0x68, 0x69, 0x6e, 0x67, 0x2d, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x64, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x63, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1196,39 +2900,82 @@ func file_proto_config_proto_rawDescGZIP() []byte {
 	return file_proto_config_proto_rawDescData
 }
 
-var file_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_proto_config_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_proto_config_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_proto_config_proto_goTypes = []any{
-	(FolderType)(0),             // 0: config.FolderType
-	(PullOrder)(0),              // 1: config.PullOrder
-	(BlockPullOrder)(0),         // 2: config.BlockPullOrder
-	(CopyRangeMethod)(0),        // 3: config.CopyRangeMethod
-	(*Configuration)(nil),       // 4: config.Configuration
-	(*SyncthingInstance)(nil),   // 5: config.SyncthingInstance
-	(*DevicePattern)(nil),       // 6: config.DevicePattern
-	(*FolderPattern)(nil),       // 7: config.FolderPattern
-	(*DeviceConfiguration)(nil), // 8: config.DeviceConfiguration
-	(*FolderConfiguration)(nil), // 9: config.FolderConfiguration
-	(*Size)(nil),                // 10: config.Size
-	(*GarbageCollection)(nil),   // 11: config.GarbageCollection
+	(HookFailurePolicy)(0),          // 0: config.HookFailurePolicy
+	(FolderType)(0),                 // 1: config.FolderType
+	(PullOrder)(0),                  // 2: config.PullOrder
+	(BlockPullOrder)(0),             // 3: config.BlockPullOrder
+	(CopyRangeMethod)(0),            // 4: config.CopyRangeMethod
+	(*Configuration)(nil),           // 5: config.Configuration
+	(*Options)(nil),                 // 6: config.Options
+	(*GUI)(nil),                     // 7: config.GUI
+	(*DesiredDevice)(nil),           // 8: config.DesiredDevice
+	(*DesiredFolder)(nil),           // 9: config.DesiredFolder
+	(*Discover)(nil),                // 10: config.Discover
+	(*AdminAPI)(nil),                // 11: config.AdminAPI
+	(*SyncthingInstance)(nil),       // 12: config.SyncthingInstance
+	(*DevicePattern)(nil),           // 13: config.DevicePattern
+	(*BandwidthWindow)(nil),         // 14: config.BandwidthWindow
+	(*FolderPattern)(nil),           // 15: config.FolderPattern
+	(*CreateDirectory)(nil),         // 16: config.CreateDirectory
+	(*Hooks)(nil),                   // 17: config.Hooks
+	(*Hook)(nil),                    // 18: config.Hook
+	(*DeviceConfiguration)(nil),     // 19: config.DeviceConfiguration
+	(*FolderConfiguration)(nil),     // 20: config.FolderConfiguration
+	(*Size)(nil),                    // 21: config.Size
+	(*GarbageCollection)(nil),       // 22: config.GarbageCollection
+	(*GarbageCollectionPolicy)(nil), // 23: config.GarbageCollectionPolicy
+	nil,                             // 24: config.DesiredDevice.InstanceSelectorEntry
+	nil,                             // 25: config.DesiredFolder.InstanceSelectorEntry
+	nil,                             // 26: config.SyncthingInstance.LabelsEntry
+	nil,                             // 27: config.DevicePattern.InstanceSelectorEntry
+	nil,                             // 28: config.DevicePattern.PropagateToEntry
+	nil,                             // 29: config.GarbageCollection.InstanceSelectorEntry
 }
 var file_proto_config_proto_depIdxs = []int32{
-	5,  // 0: config.Configuration.syncthing:type_name -> config.SyncthingInstance
-	6,  // 1: config.Configuration.pattern:type_name -> config.DevicePattern
-	11, // 2: config.Configuration.garbage_collect:type_name -> config.GarbageCollection
-	7,  // 3: config.DevicePattern.folder:type_name -> config.FolderPattern
-	8,  // 4: config.DevicePattern.settings:type_name -> config.DeviceConfiguration
-	9,  // 5: config.FolderPattern.settings:type_name -> config.FolderConfiguration
-	0,  // 6: config.FolderConfiguration.type:type_name -> config.FolderType
-	10, // 7: config.FolderConfiguration.min_disk_free:type_name -> config.Size
-	1,  // 8: config.FolderConfiguration.order:type_name -> config.PullOrder
-	2,  // 9: config.FolderConfiguration.block_pull_order:type_name -> config.BlockPullOrder
-	3,  // 10: config.FolderConfiguration.copy_range_method:type_name -> config.CopyRangeMethod
-	11, // [11:11] is the sub-list for method output_type
-	11, // [11:11] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	12, // 0: config.Configuration.syncthing:type_name -> config.SyncthingInstance
+	13, // 1: config.Configuration.pattern:type_name -> config.DevicePattern
+	22, // 2: config.Configuration.garbage_collect:type_name -> config.GarbageCollection
+	17, // 3: config.Configuration.hooks:type_name -> config.Hooks
+	11, // 4: config.Configuration.admin:type_name -> config.AdminAPI
+	10, // 5: config.Configuration.discover:type_name -> config.Discover
+	8,  // 6: config.Configuration.device:type_name -> config.DesiredDevice
+	9,  // 7: config.Configuration.folder:type_name -> config.DesiredFolder
+	6,  // 8: config.Configuration.options:type_name -> config.Options
+	7,  // 9: config.Options.gui:type_name -> config.GUI
+	19, // 10: config.DesiredDevice.settings:type_name -> config.DeviceConfiguration
+	24, // 11: config.DesiredDevice.instance_selector:type_name -> config.DesiredDevice.InstanceSelectorEntry
+	20, // 12: config.DesiredFolder.settings:type_name -> config.FolderConfiguration
+	25, // 13: config.DesiredFolder.instance_selector:type_name -> config.DesiredFolder.InstanceSelectorEntry
+	12, // 14: config.Discover.settings:type_name -> config.SyncthingInstance
+	26, // 15: config.SyncthingInstance.labels:type_name -> config.SyncthingInstance.LabelsEntry
+	15, // 16: config.DevicePattern.folder:type_name -> config.FolderPattern
+	19, // 17: config.DevicePattern.settings:type_name -> config.DeviceConfiguration
+	23, // 18: config.DevicePattern.garbage_collect:type_name -> config.GarbageCollectionPolicy
+	27, // 19: config.DevicePattern.instance_selector:type_name -> config.DevicePattern.InstanceSelectorEntry
+	28, // 20: config.DevicePattern.propagate_to:type_name -> config.DevicePattern.PropagateToEntry
+	14, // 21: config.DevicePattern.bandwidth_schedule:type_name -> config.BandwidthWindow
+	20, // 22: config.FolderPattern.settings:type_name -> config.FolderConfiguration
+	16, // 23: config.FolderPattern.create_directory:type_name -> config.CreateDirectory
+	23, // 24: config.FolderPattern.garbage_collect:type_name -> config.GarbageCollectionPolicy
+	18, // 25: config.Hooks.on_accept:type_name -> config.Hook
+	18, // 26: config.Hooks.on_deny:type_name -> config.Hook
+	18, // 27: config.Hooks.on_gc_remove:type_name -> config.Hook
+	18, // 28: config.Hooks.on_gc_alert:type_name -> config.Hook
+	0,  // 29: config.Hook.on_failure:type_name -> config.HookFailurePolicy
+	1,  // 30: config.FolderConfiguration.type:type_name -> config.FolderType
+	21, // 31: config.FolderConfiguration.min_disk_free:type_name -> config.Size
+	2,  // 32: config.FolderConfiguration.order:type_name -> config.PullOrder
+	3,  // 33: config.FolderConfiguration.block_pull_order:type_name -> config.BlockPullOrder
+	4,  // 34: config.FolderConfiguration.copy_range_method:type_name -> config.CopyRangeMethod
+	29, // 35: config.GarbageCollection.instance_selector:type_name -> config.GarbageCollection.InstanceSelectorEntry
+	36, // [36:36] is the sub-list for method output_type
+	36, // [36:36] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
 }
 
 func init() { file_proto_config_proto_init() }
@@ -1250,7 +2997,7 @@ func file_proto_config_proto_init() {
 			}
 		}
 		file_proto_config_proto_msgTypes[1].Exporter = func(v any, i int) any {
-			switch v := v.(*SyncthingInstance); i {
+			switch v := v.(*Options); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1262,7 +3009,7 @@ func file_proto_config_proto_init() {
 			}
 		}
 		file_proto_config_proto_msgTypes[2].Exporter = func(v any, i int) any {
-			switch v := v.(*DevicePattern); i {
+			switch v := v.(*GUI); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1274,7 +3021,7 @@ func file_proto_config_proto_init() {
 			}
 		}
 		file_proto_config_proto_msgTypes[3].Exporter = func(v any, i int) any {
-			switch v := v.(*FolderPattern); i {
+			switch v := v.(*DesiredDevice); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1286,7 +3033,7 @@ func file_proto_config_proto_init() {
 			}
 		}
 		file_proto_config_proto_msgTypes[4].Exporter = func(v any, i int) any {
-			switch v := v.(*DeviceConfiguration); i {
+			switch v := v.(*DesiredFolder); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1298,7 +3045,7 @@ func file_proto_config_proto_init() {
 			}
 		}
 		file_proto_config_proto_msgTypes[5].Exporter = func(v any, i int) any {
-			switch v := v.(*FolderConfiguration); i {
+			switch v := v.(*Discover); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1310,7 +3057,7 @@ func file_proto_config_proto_init() {
 			}
 		}
 		file_proto_config_proto_msgTypes[6].Exporter = func(v any, i int) any {
-			switch v := v.(*Size); i {
+			switch v := v.(*AdminAPI); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1322,6 +3069,126 @@ func file_proto_config_proto_init() {
 			}
 		}
 		file_proto_config_proto_msgTypes[7].Exporter = func(v any, i int) any {
+			switch v := v.(*SyncthingInstance); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[8].Exporter = func(v any, i int) any {
+			switch v := v.(*DevicePattern); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[9].Exporter = func(v any, i int) any {
+			switch v := v.(*BandwidthWindow); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[10].Exporter = func(v any, i int) any {
+			switch v := v.(*FolderPattern); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[11].Exporter = func(v any, i int) any {
+			switch v := v.(*CreateDirectory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[12].Exporter = func(v any, i int) any {
+			switch v := v.(*Hooks); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[13].Exporter = func(v any, i int) any {
+			switch v := v.(*Hook); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[14].Exporter = func(v any, i int) any {
+			switch v := v.(*DeviceConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[15].Exporter = func(v any, i int) any {
+			switch v := v.(*FolderConfiguration); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[16].Exporter = func(v any, i int) any {
+			switch v := v.(*Size); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_proto_config_proto_msgTypes[17].Exporter = func(v any, i int) any {
 			switch v := v.(*GarbageCollection); i {
 			case 0:
 				return &v.state
@@ -1333,14 +3200,31 @@ func file_proto_config_proto_init() {
 				return nil
 			}
 		}
+		file_proto_config_proto_msgTypes[18].Exporter = func(v any, i int) any {
+			switch v := v.(*GarbageCollectionPolicy); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
+	file_proto_config_proto_msgTypes[1].OneofWrappers = []any{}
+	file_proto_config_proto_msgTypes[2].OneofWrappers = []any{}
+	file_proto_config_proto_msgTypes[9].OneofWrappers = []any{}
+	file_proto_config_proto_msgTypes[11].OneofWrappers = []any{}
+	file_proto_config_proto_msgTypes[18].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_proto_config_proto_rawDesc,
-			NumEnums:      4,
-			NumMessages:   8,
+			NumEnums:      5,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
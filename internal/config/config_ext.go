@@ -1,17 +1,572 @@
 package config
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"net"
 	"net/netip"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"google.golang.org/protobuf/proto"
+	"kastelo.dev/syncthing-configd/internal/cron"
 )
 
 func (c *Configuration) Validate() error {
+	for _, s := range c.Syncthing {
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("syncthing %s: %w", s.DisplayName(), err)
+		}
+	}
+	for i, d := range c.Discover {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("discover #%d: %w", i, err)
+		}
+	}
+	if c.MaxVariableLength < 0 {
+		return errors.New("max_variable_length must not be negative")
+	}
+	devices := make(map[protocol.DeviceID]bool)
+	for _, d := range c.Device {
+		if err := d.Validate(); err != nil {
+			return fmt.Errorf("device %s: %w", d.Id, err)
+		}
+		id, _ := protocol.DeviceIDFromString(d.Id)
+		if devices[id] {
+			return fmt.Errorf("device %s: declared more than once", d.Id)
+		}
+		devices[id] = true
+	}
+	folders := make(map[string]bool)
+	for _, f := range c.Folder {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("folder %s: %w", f.Id, err)
+		}
+		if folders[f.Id] {
+			return fmt.Errorf("folder %s: declared more than once", f.Id)
+		}
+		folders[f.Id] = true
+	}
+	if c.ReconcileIntervalS < 0 {
+		return errors.New("reconcile_interval_s must not be negative")
+	}
+	if c.DriftCheckIntervalS < -1 {
+		return errors.New("drift_check_interval_s must be -1 or more")
+	}
+	if err := c.Options.Validate(); err != nil {
+		return fmt.Errorf("options: %w", err)
+	}
+	if err := c.Hooks.Validate(); err != nil {
+		return fmt.Errorf("hooks: %w", err)
+	}
+	if err := c.GarbageCollect.Validate(); err != nil {
+		return fmt.Errorf("garbage_collect: %w", err)
+	}
+	if err := c.Admin.Validate(); err != nil {
+		return fmt.Errorf("admin: %w", err)
+	}
+	names := make(map[string]bool)
 	for i, p := range c.Pattern {
 		if err := p.Validate(); err != nil {
 			return fmt.Errorf("pattern #%d: %w", i, err)
 		}
+		if strings.HasPrefix(p.Name, "#") {
+			return fmt.Errorf("pattern #%d: name must not start with #", i)
+		}
+		name := c.PatternName(p)
+		if names[name] {
+			return fmt.Errorf("pattern #%d: duplicate name %q", i, p.Name)
+		}
+		names[name] = true
+		if p.Name == "" && strings.Contains(name, "-") {
+			slog.Warn("Unnamed pattern has the same accept_cidr and instance_selector as an earlier one; it is identified by its position among those, so give it a name", "pattern", i, "name", name)
+		}
+	}
+	return nil
+}
+
+func (s *SyncthingInstance) Validate() error {
+	if (s.Address == "" || s.Address == "unix://") && s.SyncthingConfigXml == "" {
+		return errors.New("missing address")
+	}
+	keys := 0
+	for _, k := range []string{s.ApiKey, s.ApiKeyFile, s.ApiKeyEnv} {
+		if k != "" {
+			keys++
+		}
+	}
+	if keys > 1 {
+		return errors.New("api_key, api_key_file and api_key_env are mutually exclusive")
+	}
+	if keys == 0 && s.SyncthingConfigXml == "" {
+		return errors.New("missing API key")
+	}
+	switch s.URLScheme() {
+	case "http":
+		if s.usesTLS() {
+			return errors.New("TLS options require the https scheme")
+		}
+	case "https":
+	default:
+		return fmt.Errorf("unsupported scheme %q", s.Scheme)
+	}
+	if s.CertFingerprint != "" {
+		if _, err := ParseFingerprint(s.CertFingerprint); err != nil {
+			return err
+		}
+		if s.CaFile != "" {
+			return errors.New("cert_fingerprint and ca_file are mutually exclusive")
+		}
+	}
+	if s.InsecureSkipVerify && (s.CaFile != "" || s.CertFingerprint != "") {
+		return errors.New("insecure_skip_verify cannot be combined with ca_file or cert_fingerprint")
+	}
+	if (s.ClientCertFile == "") != (s.ClientKeyFile == "") {
+		return errors.New("client_cert_file and client_key_file must be given together")
+	}
+	for key := range s.Labels {
+		if !labelKeyRe.MatchString(key) {
+			return fmt.Errorf("label %q: must be letters, digits and underscores", key)
+		}
+	}
+	return nil
+}
+
+// labelKeyRe matches label keys that can be used as template variables.
+var labelKeyRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (d *Discover) Validate() error {
+	if d.Glob == "" {
+		return errors.New("missing glob")
+	}
+	if _, err := filepath.Match(d.Glob, ""); err != nil {
+		return fmt.Errorf("glob: %w", err)
+	}
+	if _, err := regexp.Compile(d.PathRegexp); err != nil {
+		return fmt.Errorf("path_regexp: %w", err)
+	}
+	if d.IntervalS < 0 {
+		return errors.New("interval_s must not be negative")
+	}
+	if d.Settings.GetAddress() != "" || d.Settings.GetSyncthingConfigXml() != "" || d.Settings.GetName() != "" {
+		return errors.New("settings must not contain address, syncthing_config_xml or name")
+	}
+	if d.Settings != nil {
+		s := proto.Clone(d.Settings).(*SyncthingInstance)
+		s.SyncthingConfigXml = "config.xml"
+		if err := s.Validate(); err != nil {
+			return fmt.Errorf("settings: %w", err)
+		}
+	}
+	return nil
+}
+
+// DisplayName identifies the instance in messages and templates: by its
+// configured name, its address, or its Syncthing config if the address is
+// taken from there.
+func (s *SyncthingInstance) DisplayName() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	if s.Address == "" {
+		return s.SyncthingConfigXml
+	}
+	return s.Address
+}
+
+// URLScheme returns the scheme to use for the Syncthing GUI, by default
+// https if any TLS option is set and http otherwise.
+func (s *SyncthingInstance) URLScheme() string {
+	if s.Scheme != "" {
+		return strings.ToLower(s.Scheme)
+	}
+	if s.usesTLS() {
+		return "https"
+	}
+	return "http"
+}
+
+// Matches returns true if the instance has all the labels in the
+// selector. An empty selector matches every instance.
+func (s *SyncthingInstance) Matches(selector map[string]string) bool {
+	for key, val := range selector {
+		if v, ok := s.GetLabels()[key]; !ok || v != val {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *SyncthingInstance) usesTLS() bool {
+	return s.CaFile != "" || s.CertFingerprint != "" || s.ClientCertFile != "" || s.InsecureSkipVerify
+}
+
+// ParseFingerprint parses a certificate fingerprint, the SHA-256 hash of
+// the DER encoded certificate. It's given either in hex, optionally with
+// colons, or as a Syncthing device ID (which is the same hash).
+func ParseFingerprint(s string) ([sha256.Size]byte, error) {
+	var fp [sha256.Size]byte
+	if id, err := protocol.DeviceIDFromString(s); err == nil {
+		return id, nil
+	}
+	bs, err := hex.DecodeString(strings.ReplaceAll(s, ":", ""))
+	if err != nil || len(bs) != sha256.Size {
+		return fp, fmt.Errorf("cert_fingerprint %q is neither a SHA-256 hash nor a device ID", s)
+	}
+	copy(fp[:], bs)
+	return fp, nil
+}
+
+func (d *DesiredDevice) Validate() error {
+	if _, err := protocol.DeviceIDFromString(d.Id); err != nil {
+		return fmt.Errorf("invalid device ID: %w", err)
+	}
+	return nil
+}
+
+func (f *DesiredFolder) Validate() error {
+	if f.Id == "" {
+		return errors.New("missing folder ID")
+	}
+	if f.GetSettings().GetPath() == "" {
+		return errors.New("missing path")
+	}
+	for _, dev := range f.Device {
+		if _, err := protocol.DeviceIDFromString(dev); err != nil {
+			return fmt.Errorf("device %s: %w", dev, err)
+		}
+	}
+	return nil
+}
+
+// IsProtected returns true if the device or folder ID matches any of the
+// garbage collection's protected IDs or globs, or is declared in the
+// configuration. Protected devices and folders are never removed.
+func (c *Configuration) IsProtected(id string) bool {
+	if c.IsDeclared(id) {
+		return true
+	}
+	devID, devErr := protocol.DeviceIDFromString(id)
+	for _, pat := range c.GetGarbageCollect().GetProtect() {
+		if pat == id {
+			return true
+		}
+		if ok, err := path.Match(pat, id); err == nil && ok {
+			return true
+		}
+		if devErr != nil {
+			continue
+		}
+		// Device IDs may be written in any of the forms Syncthing
+		// accepts, or as the short ID
+		if pid, err := protocol.DeviceIDFromString(pat); err == nil && pid == devID {
+			return true
+		}
+		if strings.EqualFold(pat, devID.Short().String()) {
+			return true
+		}
+		if ok, err := path.Match(strings.ToUpper(pat), id); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// validateProtect returns an error if the protect entry is not a valid
+// glob, or looks like a device ID but isn't a valid one.
+func validateProtect(pat string) error {
+	if _, err := path.Match(pat, ""); err != nil {
+		return fmt.Errorf("protect %q: %w", pat, err)
+	}
+	if strings.ContainsAny(pat, `*?[\`) {
+		return nil
+	}
+	// 52 or 56 characters of base32, with or without dashes, is meant
+	// to be a device ID
+	norm := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(pat))
+	if (len(norm) == 52 || len(norm) == 56) && strings.Trim(norm, "ABCDEFGHIJKLMNOPQRSTUVWXYZ234567") == "" {
+		if _, err := protocol.DeviceIDFromString(pat); err != nil {
+			return fmt.Errorf("protect %q: %w", pat, err)
+		}
+	}
+	return nil
+}
+
+// IsDeclared returns true if the device or folder ID is declared by a
+// top level device or folder block.
+func (c *Configuration) IsDeclared(id string) bool {
+	for _, d := range c.GetDevice() {
+		if devID, err := protocol.DeviceIDFromString(d.Id); err == nil && devID.String() == id {
+			return true
+		}
+	}
+	for _, f := range c.GetFolder() {
+		if f.Id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// PatternName returns the name of the pattern or, if it doesn't have one,
+// an identity derived from its accept_cidr and instance_selector (e.g.
+// "#3fa2c1d0"). The name is recorded with the devices and folders the
+// pattern creates, so it must not change when patterns are reordered.
+// Unnamed patterns that can't be told apart that way are numbered in
+// order, from the second one on (e.g. "#3fa2c1d0-2").
+func (c *Configuration) PatternName(p *DevicePattern) string {
+	if p == nil {
+		return ""
+	}
+	if p.Name != "" {
+		return p.Name
+	}
+	name := patternHash(p)
+	n := 1
+	for _, q := range c.GetPattern() {
+		if q == p {
+			break
+		}
+		if q.Name == "" && patternHash(q) == name {
+			n++
+		}
+	}
+	if n > 1 {
+		return fmt.Sprintf("%s-%d", name, n)
+	}
+	return name
+}
+
+func patternHash(p *DevicePattern) string {
+	cidrs := slices.Clone(p.AcceptCidr)
+	slices.Sort(cidrs)
+	selector := make([]string, 0, len(p.InstanceSelector))
+	for key, val := range p.InstanceSelector {
+		selector = append(selector, key+"="+val)
+	}
+	slices.Sort(selector)
+	h := sha256.Sum256([]byte(strings.Join(cidrs, ",") + ";" + strings.Join(selector, ",")))
+	return "#" + hex.EncodeToString(h[:4])
+}
+
+// PatternByName returns the pattern with the given name, as returned by
+// PatternName, or nil.
+func (c *Configuration) PatternByName(name string) *DevicePattern {
+	for _, p := range c.GetPattern() {
+		if c.PatternName(p) == name {
+			return p
+		}
+	}
+	return nil
+}
+
+func (g *GarbageCollection) Validate() error {
+	if g == nil {
+		return nil
+	}
+	if g.MaxRemovalsPerRun < 0 {
+		return errors.New("max_removals_per_run must not be negative")
+	}
+	if g.MaxRemovalFraction < 0 || g.MaxRemovalFraction > 1 {
+		return errors.New("max_removal_fraction must be between 0.0 and 1.0")
+	}
+	if _, err := ParseDataDisposition(g.DataDisposition); err != nil {
+		return err
+	}
+	if g.QuarantineDays < 0 {
+		return errors.New("quarantine_days must not be negative")
+	}
+	for _, pat := range g.Protect {
+		if err := validateProtect(pat); err != nil {
+			return err
+		}
+	}
+	if g.Schedule != "" {
+		if _, err := cron.Parse(g.Schedule); err != nil {
+			return err
+		}
+	}
+	if _, err := g.Location(); err != nil {
+		return err
+	}
+	if g.JitterS < 0 {
+		return errors.New("jitter_s must not be negative")
+	}
+	return nil
+}
+
+// DefaultAdminAddress is where the admin API listens when enabled without
+// a listen_address.
+const DefaultAdminAddress = "127.0.0.1:8090"
+
+func (a *AdminAPI) Validate() error {
+	if a == nil {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(a.Address())
+	if err != nil {
+		return fmt.Errorf("listen_address: %w", err)
+	}
+	if a.ApiKey == "" && !isLoopback(host) {
+		return fmt.Errorf("listen_address %s is not a loopback address; an api_key is required", a.Address())
+	}
+	return nil
+}
+
+// Address returns the address the admin API listens on, or the empty
+// string if it's not enabled.
+func (a *AdminAPI) Address() string {
+	if a == nil {
+		return ""
+	}
+	if a.ListenAddress == "" {
+		return DefaultAdminAddress
+	}
+	return a.ListenAddress
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// DefaultQuarantineDays is the delay before deleting the data of removed
+// folders when quarantine_days isn't set. There is no way to have data
+// deleted right away.
+const DefaultQuarantineDays = 7
+
+// Quarantine returns how long the data of removed folders is kept before
+// being deleted, with data_disposition "delete".
+func (g *GarbageCollection) Quarantine() time.Duration {
+	days := g.GetQuarantineDays()
+	if days == 0 {
+		days = DefaultQuarantineDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// Enabled returns true if garbage collection should run periodically.
+func (g *GarbageCollection) Enabled() bool {
+	return g.GetRunEveryS() > 0 || g.GetSchedule() != ""
+}
+
+// Location returns the time zone for the schedule, by default UTC.
+func (g *GarbageCollection) Location() (*time.Location, error) {
+	if g.GetTimezone() == "" {
+		return time.UTC, nil
+	}
+	loc, err := time.LoadLocation(g.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("timezone: %w", err)
+	}
+	return loc, nil
+}
+
+func (p *GarbageCollectionPolicy) Validate() error {
+	if p == nil || p.DataDisposition == nil {
+		return nil
+	}
+	_, err := ParseDataDisposition(*p.DataDisposition)
+	return err
+}
+
+const (
+	DataKeep   = "keep"
+	DataDelete = "delete"
+	DataMove   = "move_to"
+	DataTar    = "tar_to"
+)
+
+// DataDisposition describes what to do with the data of a garbage
+// collected folder.
+type DataDisposition struct {
+	Action string // one of the Data* constants
+	Dir    string // target directory for DataMove and DataTar
+}
+
+// ParseDataDisposition parses a data_disposition setting. The empty string
+// means to keep the data.
+func ParseDataDisposition(s string) (DataDisposition, error) {
+	action, dir, _ := strings.Cut(s, ":")
+	switch action {
+	case "", DataKeep:
+		return DataDisposition{Action: DataKeep}, nil
+	case DataDelete:
+		return DataDisposition{Action: DataDelete}, nil
+	case DataMove, DataTar:
+		if !filepath.IsAbs(dir) {
+			return DataDisposition{}, fmt.Errorf("data_disposition %q: directory must be absolute", s)
+		}
+		return DataDisposition{Action: action, Dir: dir}, nil
+	}
+	return DataDisposition{}, fmt.Errorf("data_disposition %q: unknown action", s)
+}
+
+// PathInside returns true if the path is strictly inside the root
+// directory.
+func PathInside(path, root string) bool {
+	if !filepath.IsAbs(path) || !filepath.IsAbs(root) {
+		return false
+	}
+	rel, err := filepath.Rel(filepath.Clean(root), filepath.Clean(path))
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return false
+	}
+	return true
+}
+
+func (o *Options) Validate() error {
+	if o == nil {
+		return nil
+	}
+	if o.GetUsageReportingAccepted() < -1 {
+		return errors.New("usage_reporting_accepted must be -1 or more")
+	}
+	if o.GetMaxSendKbps() < 0 || o.GetMaxRecvKbps() < 0 {
+		return errors.New("bandwidth limits must not be negative")
+	}
+	if o.GetAutoUpgradeIntervalH() < 0 || o.GetConnectionLimitMax() < 0 {
+		return errors.New("auto_upgrade_interval_h and connection_limit_max must not be negative")
+	}
+	if g := o.Gui; g != nil && g.Address != nil && *g.Address == "" {
+		return errors.New("gui: address must not be empty")
+	}
+	return nil
+}
+
+func (h *Hooks) Validate() error {
+	if h == nil {
+		return nil
+	}
+	hooks := map[string]*Hook{
+		"on_accept":    h.OnAccept,
+		"on_deny":      h.OnDeny,
+		"on_gc_remove": h.OnGcRemove,
+		"on_gc_alert":  h.OnGcAlert,
+	}
+	for name, hook := range hooks {
+		if hook == nil {
+			continue
+		}
+		if len(hook.Command) == 0 {
+			return fmt.Errorf("%s: missing command", name)
+		}
+		if hook.OnFailure == HookFailurePolicy_FAILURE_ROLLBACK && name != "on_accept" {
+			return fmt.Errorf("%s: rollback is only supported for on_accept", name)
+		}
 	}
 	return nil
 }
@@ -25,9 +580,92 @@ func (p *DevicePattern) Validate() error {
 			return fmt.Errorf("parsing %s: %w", cidr, err)
 		}
 	}
+	if p.PathRoot != "" && !filepath.IsAbs(p.PathRoot) {
+		return fmt.Errorf("path_root %s is not absolute", p.PathRoot)
+	}
+	if err := p.GarbageCollect.Validate(); err != nil {
+		return fmt.Errorf("garbage_collect: %w", err)
+	}
+	switch p.Placement {
+	case "", PlacementLeastDevices, PlacementLeastFolders, PlacementHash:
+	default:
+		return fmt.Errorf("placement %q: unknown strategy", p.Placement)
+	}
+	if p.Ttl != "" {
+		if ttl, err := time.ParseDuration(p.Ttl); err != nil {
+			return fmt.Errorf("ttl: %w", err)
+		} else if ttl <= 0 {
+			return errors.New("ttl must be positive")
+		}
+	}
+	switch p.ExpireAction {
+	case "", ExpireActionRemove, ExpireActionPause:
+	default:
+		return fmt.Errorf("expire_action %q: unknown action", p.ExpireAction)
+	}
+	for i, w := range p.BandwidthSchedule {
+		if err := w.Validate(); err != nil {
+			return fmt.Errorf("bandwidth_schedule #%d: %w", i, err)
+		}
+	}
+	for _, fld := range p.Folder {
+		if err := fld.Validate(); err != nil {
+			return fmt.Errorf("folder %s: %w", fld.Id, err)
+		}
+	}
+	return nil
+}
+
+// Strategies for choosing the instance to accept a device on.
+const (
+	PlacementLeastDevices = "least_devices"
+	PlacementLeastFolders = "least_folders"
+	PlacementHash         = "hash" // consistent hash of the device ID
+)
+
+// Actions for devices whose time to live has passed.
+const (
+	ExpireActionRemove = "remove"
+	ExpireActionPause  = "pause"
+)
+
+// TTL returns the time to live of devices accepted by the pattern, or zero
+// if they don't expire.
+func (p *DevicePattern) TTL() time.Duration {
+	ttl, err := time.ParseDuration(p.GetTtl())
+	if err != nil {
+		return 0
+	}
+	return ttl
+}
+
+func (p *FolderPattern) Validate() error {
+	if err := p.GarbageCollect.Validate(); err != nil {
+		return fmt.Errorf("garbage_collect: %w", err)
+	}
+	if p.CreateDirectory != nil {
+		if p.GetSettings().GetPath() == "" {
+			return errors.New("create_directory requires a path")
+		}
+		if _, err := p.CreateDirectory.FileMode(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// FileMode returns the parsed directory mode, or zero if no mode is set.
+func (d *CreateDirectory) FileMode() (fs.FileMode, error) {
+	if d.GetMode() == "" {
+		return 0, nil
+	}
+	mode, err := strconv.ParseUint(d.Mode, 8, 32)
+	if err != nil || mode > 0o777 {
+		return 0, fmt.Errorf("invalid mode %q (must be octal, e.g. \"0750\")", d.Mode)
+	}
+	return fs.FileMode(mode), nil
+}
+
 func (p *DevicePattern) MatchesAddress(addr netip.Addr) bool {
 	if p == nil {
 		return false
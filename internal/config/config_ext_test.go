@@ -0,0 +1,129 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPatternName(t *testing.T) {
+	t.Parallel()
+
+	a := &DevicePattern{AcceptCidr: []string{"10.0.0.0/8", "192.168.0.0/16"}}
+	b := &DevicePattern{AcceptCidr: []string{"172.16.0.0/12"}}
+	named := &DevicePattern{Name: "kiosk", AcceptCidr: []string{"10.0.0.0/8"}}
+	cfg := &Configuration{Pattern: []*DevicePattern{a, b, named}}
+
+	nameA := cfg.PatternName(a)
+	if !strings.HasPrefix(nameA, "#") || nameA == cfg.PatternName(b) {
+		t.Fatalf("unexpected names %q and %q", nameA, cfg.PatternName(b))
+	}
+	if cfg.PatternName(named) != "kiosk" {
+		t.Errorf("got %q for named pattern", cfg.PatternName(named))
+	}
+
+	// Reordering patterns, or their CIDRs, keeps the name.
+	reordered := &Configuration{Pattern: []*DevicePattern{
+		b,
+		{AcceptCidr: []string{"192.168.0.0/16", "10.0.0.0/8"}},
+		named,
+	}}
+	if p := reordered.PatternByName(nameA); p != reordered.Pattern[1] {
+		t.Errorf("pattern %q not found after reordering", nameA)
+	}
+
+	// Instance selectors are part of the identity.
+	sel := &DevicePattern{AcceptCidr: a.AcceptCidr, InstanceSelector: map[string]string{"site": "a"}}
+	if cfg.PatternName(sel) == nameA {
+		t.Error("instance selector not part of the name")
+	}
+
+	// Unnamed patterns that can't be told apart are numbered in order,
+	// and still load.
+	dup := &Configuration{Pattern: []*DevicePattern{a, {AcceptCidr: []string{"192.168.0.0/16", "10.0.0.0/8"}}}}
+	if err := dup.Validate(); err != nil {
+		t.Error(err)
+	}
+	if dup.PatternName(dup.Pattern[0]) != nameA || dup.PatternName(dup.Pattern[1]) != nameA+"-2" {
+		t.Errorf("unexpected names %q and %q", dup.PatternName(dup.Pattern[0]), dup.PatternName(dup.Pattern[1]))
+	}
+	if dup.PatternByName(nameA+"-2") != dup.Pattern[1] {
+		t.Error("numbered pattern not found by name")
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIsProtected(t *testing.T) {
+	t.Parallel()
+
+	dev := "P56IOI7-MZJNU2Y-IQGDREY-DM2MGTI-MGL3BXN-PQ6W5BM-TBBZ4TJ-XZWICQ2"
+	other := "MFZWI3D-BONSGYC-YLTMRWG-C43ENR5-QXGZDMM-FZWI3DP-BONSGYY-LTMRWAD"
+	cases := []struct {
+		protect string
+		want    bool
+	}{
+		{dev, true},
+		{strings.ToLower(dev), true},
+		{strings.ReplaceAll(dev, "-", ""), true},
+		{"P56IOI7", true},
+		{"p56ioi7", true},
+		{"P56IOI7-*", true},
+		{"p56ioi7-*", true},
+		{other, false},
+		{"MFZWI3D", false},
+	}
+	for _, tc := range cases {
+		cfg := &Configuration{GarbageCollect: &GarbageCollection{Protect: []string{tc.protect}}}
+		if err := cfg.GarbageCollect.Validate(); err != nil {
+			t.Errorf("%s: %v", tc.protect, err)
+		}
+		if got := cfg.IsProtected(dev); got != tc.want {
+			t.Errorf("%s: got %v, want %v", tc.protect, got, tc.want)
+		}
+	}
+
+	// Folder IDs are matched as given
+	cfg := &Configuration{GarbageCollect: &GarbageCollection{Protect: []string{"keep-*", "default"}}}
+	if !cfg.IsProtected("keep-this") || !cfg.IsProtected("default") || cfg.IsProtected("Default") {
+		t.Error("unexpected folder protection")
+	}
+
+	for _, bad := range []string{"[", "P56IOI7-MZJNU2Y-IQGDREY-DM2MGTI-MGL3BXN-PQ6W5BM-TBBZ4TJ-XZWICQ3"} {
+		g := &GarbageCollection{Protect: []string{bad}}
+		if err := g.Validate(); err == nil {
+			t.Errorf("expected error for %q", bad)
+		}
+	}
+}
+
+func TestAdminValidate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		admin *AdminAPI
+		ok    bool
+	}{
+		{nil, true},
+		{&AdminAPI{}, true},
+		{&AdminAPI{ListenAddress: "127.0.0.1:8090"}, true},
+		{&AdminAPI{ListenAddress: "[::1]:8090"}, true},
+		{&AdminAPI{ListenAddress: "localhost:8090"}, true},
+		{&AdminAPI{ListenAddress: ":8090"}, false},
+		{&AdminAPI{ListenAddress: "0.0.0.0:8090"}, false},
+		{&AdminAPI{ListenAddress: "192.0.2.1:8090"}, false},
+		{&AdminAPI{ListenAddress: "192.0.2.1:8090", ApiKey: "secret"}, true},
+		{&AdminAPI{ListenAddress: "8090"}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.admin.Validate(); (err == nil) != tc.ok {
+			t.Errorf("%v: got error %v", tc.admin, err)
+		}
+	}
+	if addr := (&AdminAPI{}).Address(); addr != DefaultAdminAddress {
+		t.Errorf("default address %q", addr)
+	}
+	if addr := (*AdminAPI)(nil).Address(); addr != "" {
+		t.Errorf("address %q when not enabled", addr)
+	}
+}
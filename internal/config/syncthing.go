@@ -0,0 +1,176 @@
+package config
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	stfs "github.com/syncthing/syncthing/lib/fs"
+)
+
+// SyncthingConfig returns the corresponding Syncthing device configuration,
+// without a device ID or name. A nil configuration has all default values.
+func (s *DeviceConfiguration) SyncthingConfig() stconfig.DeviceConfiguration {
+	if s == nil { // avoid having to use getters everywhere
+		s = &DeviceConfiguration{}
+	}
+	return stconfig.DeviceConfiguration{
+		Addresses:         s.Addresses,
+		AllowedNetworks:   s.AllowedNetworks,
+		AutoAcceptFolders: s.AutoAcceptFolders,
+		MaxSendKbps:       int(s.MaxSendKbps),
+		MaxRecvKbps:       int(s.MaxRecvKbps),
+		MaxRequestKiB:     int(s.MaxRequestKib),
+		RawNumConnections: int(s.NumConnections),
+	}
+}
+
+// SyncthingConfig returns the corresponding Syncthing folder configuration,
+// without a folder ID or devices. A nil configuration has all default
+// values.
+func (s *FolderConfiguration) SyncthingConfig() stconfig.FolderConfiguration {
+	if s == nil { // avoid having to use getters everywhere
+		s = &FolderConfiguration{}
+	}
+	return stconfig.FolderConfiguration{
+		Path:             s.Path,
+		Type:             stconfig.FolderType(s.Type),
+		Label:            s.Label,
+		RescanIntervalS:  int(s.RescanIntervalS),
+		FSWatcherEnabled: !s.FsWatcherDisabled,
+		FSWatcherDelayS:  s.FsWatcherDelayS,
+		IgnorePerms:      s.IgnorePermissions,
+		AutoNormalize:    !s.NoAutoNormalize,
+		MinDiskFree: stconfig.Size{
+			Value: s.GetMinDiskFree().GetValue(),
+			Unit:  s.GetMinDiskFree().GetUnit(),
+		},
+		Copiers:                 int(s.Copiers),
+		PullerMaxPendingKiB:     int(s.PullerMaxPendingKib),
+		Hashers:                 int(s.Hashers),
+		Order:                   stconfig.PullOrder(s.Order),
+		IgnoreDelete:            s.IgnoreDelete,
+		ScanProgressIntervalS:   int(s.ScanProgressIntervalS),
+		PullerPauseS:            int(s.PullerPauseS),
+		MaxConflicts:            int(s.MaxConflicts),
+		DisableSparseFiles:      s.DisableSparseFiles,
+		DisableTempIndexes:      s.DisableTempIndexes,
+		WeakHashThresholdPct:    int(s.WeakHashThresholdPct),
+		MarkerName:              s.MarkerName,
+		CopyOwnershipFromParent: s.CopyOwnershipFromParent,
+		RawModTimeWindowS:       int(s.ModTimeWindowS),
+		MaxConcurrentWrites:     int(s.MaxConcurrentWrites),
+		DisableFsync:            s.DisableFsync,
+		BlockPullOrder:          stconfig.BlockPullOrder(s.BlockPullOrder),
+		CopyRangeMethod:         stfs.CopyRangeMethod(s.CopyRangeMethod),
+		CaseSensitiveFS:         s.CaseSensitiveFs,
+		JunctionsAsDirs:         s.FollowJunctions,
+		SyncOwnership:           s.SyncOwnership,
+		SendOwnership:           s.SendOwnership,
+		SyncXattrs:              s.SyncXattrs,
+		SendXattrs:              s.SendXattrs,
+	}
+}
+
+// Overlay sets the fields of dst, a pointer to a Syncthing configuration
+// struct, that are set in want, i.e. differ between want and unset (the
+// configuration resulting from no settings at all). This way only the
+// settings actually given in the configuration are applied. Fields named
+// in always (by JSON name) are known to be given and are applied even when
+// equal to unset. It returns the JSON names of the fields that were
+// changed.
+func Overlay(dst any, want, unset any, always ...string) []string {
+	d := reflect.ValueOf(dst).Elem()
+	w := reflect.ValueOf(want)
+	u := reflect.ValueOf(unset)
+	var changed []string
+	for i := 0; i < d.NumField(); i++ {
+		if !d.Field(i).CanSet() {
+			continue
+		}
+		name, _, _ := strings.Cut(d.Type().Field(i).Tag.Get("json"), ",")
+		if name == "" {
+			name = d.Type().Field(i).Name
+		}
+		wf := w.Field(i).Interface()
+		if reflect.DeepEqual(wf, d.Field(i).Interface()) {
+			continue
+		}
+		if !slices.Contains(always, name) && reflect.DeepEqual(wf, u.Field(i).Interface()) {
+			continue
+		}
+		d.Field(i).Set(w.Field(i))
+		changed = append(changed, name)
+	}
+	return changed
+}
+
+// Apply sets the options that are set in o on cur, returning the JSON
+// names of the options that were changed.
+func (o *Options) Apply(cur *stconfig.OptionsConfiguration) []string {
+	if o == nil {
+		return nil
+	}
+	var changed []string
+	setStrings(&changed, "listenAddresses", &cur.RawListenAddresses, o.GetListenAddresses())
+	setStrings(&changed, "globalAnnounceServers", &cur.RawGlobalAnnServers, o.GetGlobalDiscoveryServers())
+	setValue(&changed, "globalAnnounceEnabled", &cur.GlobalAnnEnabled, o.GlobalDiscoveryEnabled)
+	setValue(&changed, "localAnnounceEnabled", &cur.LocalAnnEnabled, o.LocalDiscoveryEnabled)
+	setValue(&changed, "relaysEnabled", &cur.RelaysEnabled, o.RelaysEnabled)
+	setValue(&changed, "natEnabled", &cur.NATEnabled, o.NatTraversalEnabled)
+	setInt(&changed, "maxSendKbps", &cur.MaxSendKbps, o.MaxSendKbps)
+	setInt(&changed, "maxRecvKbps", &cur.MaxRecvKbps, o.MaxRecvKbps)
+	setValue(&changed, "limitBandwidthInLan", &cur.LimitBandwidthInLan, o.LimitBandwidthInLan)
+	setInt(&changed, "urAccepted", &cur.URAccepted, o.UsageReportingAccepted)
+	setValue(&changed, "crashReportingEnabled", &cur.CREnabled, o.CrashReportingEnabled)
+	setInt(&changed, "autoUpgradeIntervalH", &cur.AutoUpgradeIntervalH, o.AutoUpgradeIntervalH)
+	setValue(&changed, "startBrowser", &cur.StartBrowser, o.StartBrowser)
+	setStrings(&changed, "alwaysLocalNets", &cur.AlwaysLocalNets, o.GetAlwaysLocalNets())
+	setInt(&changed, "connectionLimitMax", &cur.ConnectionLimitMax, o.ConnectionLimitMax)
+	return changed
+}
+
+// Apply sets the GUI settings that are set in g on cur, returning the JSON
+// names of the settings that were changed. The password is compared with
+// the hash Syncthing keeps, and set in plain text for Syncthing to hash.
+func (g *GUI) Apply(cur *stconfig.GUIConfiguration) []string {
+	if g == nil {
+		return nil
+	}
+	var changed []string
+	setValue(&changed, "address", &cur.RawAddress, g.Address)
+	setValue(&changed, "useTLS", &cur.RawUseTLS, g.UseTls)
+	setValue(&changed, "user", &cur.User, g.User)
+	if g.Password != nil && cur.CompareHashedPassword(*g.Password) != nil {
+		cur.Password = *g.Password
+		changed = append(changed, "password")
+	}
+	setValue(&changed, "theme", &cur.Theme, g.Theme)
+	setValue(&changed, "insecureAdminAccess", &cur.InsecureAdminAccess, g.InsecureAdminAccess)
+	setValue(&changed, "insecureSkipHostcheck", &cur.InsecureSkipHostCheck, g.InsecureSkipHostCheck)
+	return changed
+}
+
+// setValue sets dst to the value of the optional field want, if it is set
+// and differs.
+func setValue[T comparable](changed *[]string, name string, dst *T, want *T) {
+	if want != nil && *dst != *want {
+		*dst = *want
+		*changed = append(*changed, name)
+	}
+}
+
+func setInt(changed *[]string, name string, dst *int, want *int32) {
+	if want != nil && *dst != int(*want) {
+		*dst = int(*want)
+		*changed = append(*changed, name)
+	}
+}
+
+func setStrings(changed *[]string, name string, dst *[]string, want []string) {
+	if len(want) > 0 && !slices.Equal(*dst, want) {
+		*dst = want
+		*changed = append(*changed, name)
+	}
+}
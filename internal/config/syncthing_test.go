@@ -0,0 +1,69 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+)
+
+func TestOptionsApply(t *testing.T) {
+	t.Parallel()
+
+	no, yes := false, true
+	declined := int32(-1)
+	opts := &Options{
+		ListenAddresses:        []string{"tcp://0.0.0.0:22000"},
+		RelaysEnabled:          &no,
+		GlobalDiscoveryEnabled: &no,
+		LocalDiscoveryEnabled:  &yes,
+		UsageReportingAccepted: &declined,
+	}
+
+	cur := stconfig.OptionsConfiguration{
+		RawListenAddresses: []string{"default"},
+		RelaysEnabled:      true,
+		GlobalAnnEnabled:   true,
+		LocalAnnEnabled:    true,
+		URAccepted:         3,
+		MaxSendKbps:        100,
+	}
+	changed := opts.Apply(&cur)
+	want := []string{"listenAddresses", "globalAnnounceEnabled", "relaysEnabled", "urAccepted"}
+	if !slices.Equal(changed, want) {
+		t.Errorf("changed %v, want %v", changed, want)
+	}
+	if cur.RelaysEnabled || cur.GlobalAnnEnabled || !cur.LocalAnnEnabled || cur.URAccepted != -1 || cur.MaxSendKbps != 100 {
+		t.Errorf("unexpected options %+v", cur)
+	}
+	if changed := opts.Apply(&cur); len(changed) != 0 {
+		t.Errorf("second apply changed %v", changed)
+	}
+	if changed := (*Options)(nil).Apply(&cur); len(changed) != 0 {
+		t.Errorf("nil options changed %v", changed)
+	}
+}
+
+func TestGUIApply(t *testing.T) {
+	t.Parallel()
+
+	user, password, theme := "admin", "secret", "dark"
+	gui := &GUI{User: &user, Password: &password, Theme: &theme}
+
+	cur := stconfig.GUIConfiguration{RawAddress: "127.0.0.1:8384", Theme: "dark"}
+	changed := gui.Apply(&cur)
+	if !slices.Equal(changed, []string{"user", "password"}) {
+		t.Errorf("unexpected changes %v", changed)
+	}
+	if cur.Password != password || cur.RawAddress != "127.0.0.1:8384" {
+		t.Errorf("unexpected settings %+v", cur)
+	}
+
+	// Syncthing keeps the password hashed
+	if err := cur.SetPassword(password); err != nil {
+		t.Fatal(err)
+	}
+	if changed := gui.Apply(&cur); len(changed) != 0 {
+		t.Errorf("hashed password changed %v", changed)
+	}
+}
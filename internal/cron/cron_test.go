@@ -0,0 +1,68 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseErrors(t *testing.T) {
+	t.Parallel()
+
+	cases := []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"5-1 * * * *",
+		"*/0 * * * *",
+		"a * * * *",
+		"@sometimes",
+	}
+	for _, c := range cases {
+		if _, err := Parse(c); err == nil {
+			t.Errorf("Parse(%q) returned nil error", c)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	t.Parallel()
+
+	stockholm, err := time.LoadLocation("Europe/Stockholm")
+	if err != nil {
+		t.Skip("no time zone data:", err)
+	}
+	from := time.Date(2024, 8, 15, 12, 34, 56, 0, time.UTC) // a Thursday
+
+	cases := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", from, time.Date(2024, 8, 15, 12, 35, 0, 0, time.UTC)},
+		{"@daily", from, time.Date(2024, 8, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", from, time.Date(2024, 8, 15, 13, 0, 0, 0, time.UTC)},
+		{"30 2 * * *", from, time.Date(2024, 8, 16, 2, 30, 0, 0, time.UTC)},
+		{"*/15 * * * *", from, time.Date(2024, 8, 15, 12, 45, 0, 0, time.UTC)},
+		{"0 9-17/4 * * *", from, time.Date(2024, 8, 15, 13, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", from, time.Date(2024, 8, 18, 0, 0, 0, 0, time.UTC)},   // Sunday
+		{"0 0 * * 1-5", from, time.Date(2024, 8, 16, 0, 0, 0, 0, time.UTC)}, // weekdays
+		{"0 0 1 * *", from, time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * 1", from, time.Date(2024, 8, 19, 0, 0, 0, 0, time.UTC)}, // first, or a Monday
+		{"0 0 29 2 *", from, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", from, time.Time{}},
+		{"0 3 * * *", from.In(stockholm), time.Date(2024, 8, 16, 3, 0, 0, 0, stockholm)},
+	}
+	for _, c := range cases {
+		s, err := Parse(c.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", c.expr, err)
+		}
+		if got := s.Next(c.from); !got.Equal(c.want) {
+			t.Errorf("Parse(%q).Next(%v) = %v, want %v", c.expr, c.from, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package discover finds Syncthing instances by their home directories and
+// starts and stops the services for each as they come and go.
+package discover
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/thejerf/suture/v4"
+	"google.golang.org/protobuf/proto"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+const (
+	defaultInterval = time.Minute
+	removeTimeout   = 10 * time.Second
+)
+
+// A Starter adds the services for an instance to the given supervisor. The
+// returned function, if any, is called after the services have been
+// stopped for good.
+type Starter func(sup *suture.Supervisor, inst *config.SyncthingInstance) (func(), error)
+
+type Discoverer struct {
+	log     *slog.Logger
+	cfg     *config.Discover
+	re      *regexp.Regexp
+	sup     *suture.Supervisor
+	start   Starter
+	running map[string]instance // by home directory
+}
+
+type instance struct {
+	token suture.ServiceToken
+	stop  func()
+}
+
+// New returns a Discoverer that adds the discovered instances to the given
+// supervisor.
+func New(log *slog.Logger, cfg *config.Discover, sup *suture.Supervisor, start Starter) (*Discoverer, error) {
+	var re *regexp.Regexp
+	if cfg.PathRegexp != "" {
+		var err error
+		re, err = regexp.Compile(cfg.PathRegexp)
+		if err != nil {
+			return nil, fmt.Errorf("path_regexp: %w", err)
+		}
+	}
+	return &Discoverer{
+		log:     log.With("glob", cfg.Glob),
+		cfg:     cfg,
+		re:      re,
+		sup:     sup,
+		start:   start,
+		running: make(map[string]instance),
+	}, nil
+}
+
+func (d *Discoverer) Serve(ctx context.Context) error {
+	interval := time.Duration(d.cfg.IntervalS) * time.Second
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	for {
+		d.scan()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (d *Discoverer) String() string {
+	return fmt.Sprintf("discoverer(%s)@%p", d.cfg.Glob, d)
+}
+
+// scan starts services for new instances and stops those for instances
+// that have disappeared.
+func (d *Discoverer) scan() {
+	dirs, err := filepath.Glob(d.cfg.Glob)
+	if err != nil {
+		d.log.Error("Failed to discover instances", "error", err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		cfgPath := filepath.Join(dir, "config.xml")
+		if _, err := os.Stat(cfgPath); err != nil {
+			continue
+		}
+		labels, ok := d.labels(dir)
+		if !ok {
+			d.log.Debug("Skipping directory not matching path_regexp", "dir", dir)
+			continue
+		}
+		seen[dir] = true
+		if _, ok := d.running[dir]; ok {
+			continue
+		}
+
+		inst := &config.SyncthingInstance{}
+		if d.cfg.Settings != nil {
+			inst = proto.Clone(d.cfg.Settings).(*config.SyncthingInstance)
+		}
+		inst.SyncthingConfigXml = cfgPath
+		inst.Name = dir
+		if len(labels) > 0 && inst.Labels == nil {
+			inst.Labels = make(map[string]string)
+		}
+		for k, v := range labels {
+			inst.Labels[k] = v
+		}
+
+		// Failures are retried on the next scan, as the instance may
+		// still be in the process of being set up.
+		sup := suture.NewSimple("instance " + dir)
+		stop, err := d.start(sup, inst)
+		if err != nil {
+			d.log.Warn("Failed to start discovered instance", "dir", dir, "error", err)
+			continue
+		}
+		d.log.Info("Discovered Syncthing instance", "dir", dir, "labels", inst.Labels)
+		d.running[dir] = instance{token: d.sup.Add(sup), stop: stop}
+	}
+
+	for dir, inst := range d.running {
+		if seen[dir] {
+			continue
+		}
+		d.log.Info("Syncthing instance disappeared", "dir", dir)
+		if err := d.sup.RemoveAndWait(inst.token, removeTimeout); err != nil {
+			d.log.Warn("Failed to stop services for instance", "dir", dir, "error", err)
+		}
+		if inst.stop != nil {
+			inst.stop()
+		}
+		delete(d.running, dir)
+	}
+}
+
+// labels returns the named groups of the path regexp as matched against
+// the directory, or false if it doesn't match.
+func (d *Discoverer) labels(dir string) (map[string]string, bool) {
+	if d.re == nil {
+		return nil, true
+	}
+	m := d.re.FindStringSubmatch(dir)
+	if m == nil {
+		return nil, false
+	}
+	labels := make(map[string]string)
+	for i, name := range d.re.SubexpNames() {
+		if name != "" {
+			labels[name] = m[i]
+		}
+	}
+	return labels, true
+}
@@ -0,0 +1,113 @@
+package discover
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/thejerf/suture/v4"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	mkHome := func(tenant string) {
+		dir := filepath.Join(root, tenant, "syncthing")
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "config.xml"), []byte("<configuration/>"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mkHome("alice")
+	mkHome("bob")
+	mkHome("_skel")
+	// A directory without config.xml is not an instance
+	if err := os.MkdirAll(filepath.Join(root, "carol", "syncthing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var mut sync.Mutex
+	started := make(map[string]*config.SyncthingInstance)
+	stopped := make(map[string]bool)
+	start := func(_ *suture.Supervisor, inst *config.SyncthingInstance) (func(), error) {
+		mut.Lock()
+		defer mut.Unlock()
+		if inst.ApiKeyEnv != "API_KEY" {
+			t.Errorf("settings not applied: %v", inst)
+		}
+		started[inst.SyncthingConfigXml] = inst
+		return func() {
+			mut.Lock()
+			defer mut.Unlock()
+			stopped[inst.SyncthingConfigXml] = true
+		}, nil
+	}
+
+	cfg := &config.Discover{
+		Glob:       filepath.Join(root, "*", "syncthing"),
+		PathRegexp: `/(?P<tenant>[a-z]+)/syncthing$`,
+		Settings: &config.SyncthingInstance{
+			ApiKeyEnv: "API_KEY",
+			Labels:    map[string]string{"role": "tenant", "tenant": "overridden"},
+		},
+	}
+	sup := suture.NewSimple("test")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sup.ServeBackground(ctx)
+
+	d, err := New(slog.Default(), cfg, sup, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.scan()
+
+	alice := filepath.Join(root, "alice", "syncthing", "config.xml")
+	bob := filepath.Join(root, "bob", "syncthing", "config.xml")
+	mut.Lock()
+	if len(started) != 2 {
+		t.Errorf("expected two started instances, got %v", started)
+	}
+	for tenant, path := range map[string]string{"alice": alice, "bob": bob} {
+		inst := started[path]
+		if inst == nil {
+			t.Errorf("instance %s not started", tenant)
+			continue
+		}
+		if inst.Labels["tenant"] != tenant || inst.Labels["role"] != "tenant" {
+			t.Errorf("unexpected labels for %s: %v", tenant, inst.Labels)
+		}
+		if inst.Name != filepath.Dir(path) {
+			t.Errorf("unexpected name for %s: %q", tenant, inst.Name)
+		}
+	}
+	if cfg.Settings.Labels["tenant"] != "overridden" {
+		t.Error("settings were modified")
+	}
+	mut.Unlock()
+
+	// Remove one instance and scan again
+	if err := os.RemoveAll(filepath.Join(root, "bob")); err != nil {
+		t.Fatal(err)
+	}
+	d.scan()
+
+	mut.Lock()
+	defer mut.Unlock()
+	if len(started) != 2 {
+		t.Errorf("instances should not be started again: %v", started)
+	}
+	if !stopped[bob] || stopped[alice] {
+		t.Errorf("unexpected stopped instances: %v", stopped)
+	}
+	if len(d.running) != 1 {
+		t.Errorf("expected one running instance, got %d", len(d.running))
+	}
+}
@@ -0,0 +1,85 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+const defaultDirectoryMode = 0o700
+
+// createFolderDirectory creates the directory for the given folder,
+// including the folder marker if requested, with the configured ownership
+// and permissions. Existing directories are left in place but get their
+// ownership and permissions updated.
+func createFolderDirectory(fld *stconfig.FolderConfiguration, cd *config.CreateDirectory) error {
+	if fld.Path == "" {
+		return errors.New("folder has no path")
+	}
+	if !filepath.IsAbs(fld.Path) {
+		return fmt.Errorf("folder path %s is not absolute", fld.Path)
+	}
+
+	mode, err := cd.FileMode()
+	if err != nil {
+		return err
+	}
+	if mode == 0 {
+		mode = defaultDirectoryMode
+	}
+
+	if err := makeDirectory(fld.Path, mode, cd); err != nil {
+		return err
+	}
+
+	if cd.Marker {
+		marker := fld.MarkerName
+		if marker == "" {
+			marker = stconfig.DefaultMarkerName
+		}
+		if err := makeDirectory(filepath.Join(fld.Path, marker), mode, cd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func makeDirectory(path string, mode fs.FileMode, cd *config.CreateDirectory) error {
+	if err := os.MkdirAll(path, mode); err != nil {
+		return fmt.Errorf("creating directory: %w", err)
+	}
+	// MkdirAll is satisfied by a symlink to a directory, and Chmod would
+	// follow it. The path comes from device supplied variables, so refuse
+	// anything but a real directory.
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("checking directory: %w", err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s exists and is not a directory", path)
+	}
+	// Set the mode explicitly, as MkdirAll is subject to the umask and
+	// doesn't touch existing directories.
+	if err := os.Chmod(path, mode); err != nil {
+		return fmt.Errorf("setting mode: %w", err)
+	}
+	if cd.Uid != nil || cd.Gid != nil {
+		uid, gid := -1, -1
+		if cd.Uid != nil {
+			uid = int(*cd.Uid)
+		}
+		if cd.Gid != nil {
+			gid = int(*cd.Gid)
+		}
+		if err := os.Lchown(path, uid, gid); err != nil {
+			return fmt.Errorf("setting ownership: %w", err)
+		}
+	}
+	return nil
+}
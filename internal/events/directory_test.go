@@ -0,0 +1,82 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+func TestCreateFolderDirectory(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	uid, gid := int32(os.Getuid()), int32(os.Getgid())
+
+	fld := &stconfig.FolderConfiguration{
+		ID:   "test",
+		Path: filepath.Join(root, "devices", "test"),
+	}
+	cd := &config.CreateDirectory{
+		Uid:    &uid,
+		Gid:    &gid,
+		Mode:   "0750",
+		Marker: true,
+	}
+
+	if err := createFolderDirectory(fld, cd); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(fld.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDir() {
+		t.Fatal("folder path is not a directory")
+	}
+	if perm := info.Mode().Perm(); perm != 0o750 {
+		t.Errorf("got mode %o, want 750", perm)
+	}
+	if _, err := os.Stat(filepath.Join(fld.Path, stconfig.DefaultMarkerName)); err != nil {
+		t.Errorf("marker not created: %v", err)
+	}
+
+	// Creating it again, with a different mode, updates the mode.
+	cd.Mode = "0700"
+	if err := createFolderDirectory(fld, cd); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(fld.Path); err != nil {
+		t.Fatal(err)
+	} else if perm := info.Mode().Perm(); perm != 0o700 {
+		t.Errorf("got mode %o, want 700", perm)
+	}
+
+	// A symlink at the folder path is refused, and what it points to is
+	// left alone.
+	target := filepath.Join(root, "target")
+	if err := os.Mkdir(target, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fld.Path = filepath.Join(root, "devices", "link")
+	if err := os.Symlink(target, fld.Path); err != nil {
+		t.Fatal(err)
+	}
+	if err := createFolderDirectory(fld, cd); err == nil {
+		t.Error("unexpected nil error for symlink")
+	}
+	if info, err := os.Stat(target); err != nil {
+		t.Fatal(err)
+	} else if perm := info.Mode().Perm(); perm != 0o755 {
+		t.Errorf("symlink target mode changed to %o", perm)
+	}
+
+	// Relative paths are refused.
+	fld.Path = "relative"
+	if err := createFolderDirectory(fld, cd); err == nil {
+		t.Error("unexpected nil error for relative path")
+	}
+}
@@ -0,0 +1,316 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/admin"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+const (
+	defaultDriftCheckInterval = time.Hour
+	driftRetryInterval        = 10 * time.Second
+)
+
+var (
+	metricDrifted = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "syncthing_configd",
+		Subsystem: "drift",
+		Name:      "objects",
+		Help:      "Number of managed devices and folders whose settings differ from their pattern.",
+	}, []string{"instance", "kind"})
+	metricCorrected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "syncthing_configd",
+		Subsystem: "drift",
+		Name:      "corrected_total",
+		Help:      "Number of times pattern settings were re-applied to a drifted device or folder.",
+	}, []string{"instance", "kind"})
+)
+
+// Drift is a managed device or folder whose settings differ from what its
+// pattern currently results in.
+type Drift struct {
+	Kind      string   `json:"kind"` // "device" or "folder"
+	ID        string   `json:"id"`
+	Pattern   string   `json:"pattern"`
+	Fields    []string `json:"fields"` // Syncthing configuration field names
+	Corrected bool     `json:"corrected"`
+}
+
+// DriftStatus is the outcome of the latest drift check of an instance.
+type DriftStatus struct {
+	Instance  string    `json:"instance"`
+	LastCheck time.Time `json:"lastCheck,omitempty"`
+	LastError string    `json:"lastError,omitempty"`
+	Drifted   []Drift   `json:"drifted"`
+}
+
+// DriftChecker periodically compares the devices and folders accepted by
+// an event listener with what their patterns would produce today, and
+// re-applies the settings for patterns with enforce set.
+type DriftChecker struct {
+	el *EventListener
+
+	mut    sync.Mutex
+	status DriftStatus
+}
+
+func NewDriftChecker(el *EventListener) *DriftChecker {
+	return &DriftChecker{
+		el:     el,
+		status: DriftStatus{Instance: el.api.Address()},
+	}
+}
+
+func (d *DriftChecker) Serve(ctx context.Context) error {
+	interval := time.Duration(d.el.patterns.DriftCheckIntervalS) * time.Second
+	if interval <= 0 {
+		interval = defaultDriftCheckInterval
+	}
+	defer metricDrifted.DeletePartialMatch(prometheus.Labels{"instance": d.el.api.Address()})
+
+	for {
+		wait := interval
+		drifts, err := d.check()
+		d.mut.Lock()
+		if err != nil {
+			if errors.Is(err, errNotConnected) {
+				d.el.log.Debug("Postponing drift check until connected")
+			} else {
+				d.el.log.Error("Failed to check for drift", "error", err)
+				d.status.LastError = err.Error()
+			}
+			wait = driftRetryInterval
+		} else {
+			d.status.LastCheck = time.Now()
+			d.status.LastError = ""
+			d.status.Drifted = drifts
+		}
+		d.mut.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (d *DriftChecker) String() string {
+	return fmt.Sprintf("driftChecker(%s)@%p", d.el.api.Address(), d)
+}
+
+// Status returns the outcome of the latest drift check.
+func (d *DriftChecker) Status() DriftStatus {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+	st := d.status
+	st.Drifted = append([]Drift{}, st.Drifted...)
+	return st
+}
+
+// check looks for drifted devices and folders, corrects them where the
+// pattern is enforced, and updates the metrics.
+func (d *DriftChecker) check() ([]Drift, error) {
+	s := d.el
+	myID := s.api.Identity()
+	if myID == protocol.EmptyDeviceID {
+		return nil, errNotConnected
+	}
+	managed := s.state.Instance(myID)
+
+	var drifts []Drift
+	err := s.api.InConfigTx(func(tx *api.ConfigTx) error {
+		stat, err := tx.GetSystemStatus()
+		if err != nil {
+			return err
+		}
+		if err := s.api.VerifyIdentity(stat.MyID); err != nil {
+			return err
+		}
+		drifts = nil
+		for _, obj := range findDrift(s.log, tx.Config, managed, s.patterns, s.instance, time.Now()) {
+			l := s.log.With("pattern", obj.Pattern, obj.Kind, obj.ID)
+			obj.Corrected = d.correct(l, obj.pat, obj.Drift, func() error {
+				if obj.device != nil {
+					return tx.PutDevice(*obj.device)
+				}
+				return tx.PutFolder(*obj.folder)
+			})
+			drifts = append(drifts, obj.Drift)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int{"device": 0, "folder": 0}
+	for _, drift := range drifts {
+		if !drift.Corrected {
+			counts[drift.Kind]++
+		}
+	}
+	for kind, n := range counts {
+		metricDrifted.WithLabelValues(s.api.Address(), kind).Set(float64(n))
+	}
+	return drifts, nil
+}
+
+// driftedObject is a drifted device or folder, with its configuration
+// corrected to match the pattern.
+type driftedObject struct {
+	Drift
+	pat    *config.DevicePattern
+	device *stconfig.DeviceConfiguration
+	folder *stconfig.FolderConfiguration
+}
+
+// findDrift recomputes the pattern settings for each managed device and
+// folder, from the name and address the device had when accepted, and
+// compares them to the live configuration. Only the settings given in the
+// pattern are compared, i.e. those not at their default value, plus the
+// bandwidth limits scheduled at now. Folder paths and sharing are never
+// changed.
+func findDrift(l *slog.Logger, cfg *stconfig.Configuration, managed state.Instance, patterns *config.Configuration, instance *config.SyncthingInstance, now time.Time) []driftedObject {
+	ids := make([]protocol.DeviceID, 0, len(managed.Devices))
+	for id := range managed.Devices {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(a, b int) bool {
+		return ids[a].Compare(ids[b]) < 0
+	})
+
+	unsetDevice := (*config.DeviceConfiguration)(nil).SyncthingConfig()
+	unsetFolder := (*config.FolderConfiguration)(nil).SyncthingConfig()
+	var res []driftedObject
+	checked := make(map[string]bool) // folder IDs
+	for _, id := range ids {
+		dev := managed.Devices[id]
+		pat := patterns.PatternByName(dev.Pattern)
+		if pat == nil || !instance.Matches(pat.InstanceSelector) {
+			continue
+		}
+
+		if cur, _, ok := cfg.Device(id); ok {
+			if fields := config.Overlay(&cur, pat.DeviceSettings(now), unsetDevice, pat.ScheduledLimits(now)...); len(fields) > 0 {
+				res = append(res, driftedObject{
+					Drift:  Drift{Kind: "device", ID: id.String(), Pattern: dev.Pattern, Fields: fields},
+					pat:    pat,
+					device: &cur,
+				})
+			}
+		}
+
+		addr, _ := netip.ParseAddr(dev.Address)
+		data := &deviceRejectedData{
+			name:     dev.Name,
+			device:   id,
+			address:  addr,
+			time:     dev.Accepted,
+			instance: instance,
+		}
+		_, wantFolders, err := patternConfigs(pat, data, patterns)
+		if err != nil {
+			l.Warn("Failed to apply pattern when checking for drift", "device", id, "pattern", dev.Pattern, "error", err)
+			continue
+		}
+		for _, want := range wantFolders {
+			if checked[want.ID] {
+				continue
+			}
+			checked[want.ID] = true
+			if _, ok := managed.Folders[want.ID]; !ok {
+				continue
+			}
+			cur, _, ok := cfg.Folder(want.ID)
+			if !ok {
+				continue
+			}
+			want.ID, want.Path, want.Devices = "", "", nil
+			if fields := config.Overlay(&cur, *want, unsetFolder); len(fields) > 0 {
+				res = append(res, driftedObject{
+					Drift:  Drift{Kind: "folder", ID: cur.ID, Pattern: dev.Pattern, Fields: fields},
+					pat:    pat,
+					folder: &cur,
+				})
+			}
+		}
+	}
+	return res
+}
+
+// correct logs the drift and, if the pattern is enforced, re-applies the
+// settings using put. It returns whether the drift was corrected.
+func (d *DriftChecker) correct(l *slog.Logger, pat *config.DevicePattern, drift Drift, put func() error) bool {
+	if !pat.Enforce {
+		l.Warn("Settings differ from pattern", "kind", drift.Kind, "fields", drift.Fields)
+		return false
+	}
+	if err := put(); err != nil {
+		l.Error("Failed to re-apply pattern settings", "kind", drift.Kind, "fields", drift.Fields, "error", err)
+		return false
+	}
+	l.Info("Re-applied pattern settings", "kind", drift.Kind, "fields", drift.Fields)
+	metricCorrected.WithLabelValues(d.el.api.Address(), drift.Kind).Inc()
+	return true
+}
+
+// DriftCheckers is the set of running drift checkers, by instance address,
+// for use by the admin API.
+type DriftCheckers struct {
+	mut      sync.Mutex
+	checkers map[string]*DriftChecker
+}
+
+func NewDriftCheckers() *DriftCheckers {
+	return &DriftCheckers{checkers: make(map[string]*DriftChecker)}
+}
+
+func (c *DriftCheckers) Add(d *DriftChecker) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.checkers[d.el.api.Address()] = d
+}
+
+func (c *DriftCheckers) Remove(d *DriftChecker) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if c.checkers[d.el.api.Address()] == d {
+		delete(c.checkers, d.el.api.Address())
+	}
+}
+
+func (c *DriftCheckers) statuses() []DriftStatus {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	res := make([]DriftStatus, 0, len(c.checkers))
+	for _, d := range c.checkers {
+		res = append(res, d.Status())
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Instance < res[b].Instance
+	})
+	return res
+}
+
+// RegisterAdmin adds the drift endpoint to the admin API.
+func (c *DriftCheckers) RegisterAdmin(srv *admin.Server) {
+	srv.Handle(http.MethodGet, "/rest/drift", func(_ *http.Request) (any, error) {
+		return c.statuses(), nil
+	})
+}
@@ -0,0 +1,107 @@
+package events
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"google.golang.org/protobuf/proto"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+func TestFindDrift(t *testing.T) {
+	t.Parallel()
+
+	okID := protocol.DeviceID{1}
+	driftID := protocol.DeviceID{2}
+	orphanID := protocol.DeviceID{3}
+
+	patterns := &config.Configuration{
+		Pattern: []*config.DevicePattern{
+			{
+				Name:     "kiosk",
+				Settings: &config.DeviceConfiguration{MaxSendKbps: 200},
+				Folder: []*config.FolderPattern{
+					{
+						Id:       "${name}",
+						Settings: &config.FolderConfiguration{Path: "/data/${name}", Label: "Kiosk ${name}", RescanIntervalS: 60},
+					},
+				},
+			},
+		},
+	}
+	instance := &config.SyncthingInstance{Address: "127.0.0.1:8384"}
+
+	folder := func(id string, rescan int, label string) stconfig.FolderConfiguration {
+		return stconfig.FolderConfiguration{
+			ID:               id,
+			Label:            label,
+			Path:             "/elsewhere/" + id, // moved by hand, not drift
+			RescanIntervalS:  rescan,
+			FSWatcherEnabled: true,
+			AutoNormalize:    true,
+			Devices:          []stconfig.FolderDeviceConfiguration{{DeviceID: protocol.LocalDeviceID}},
+		}
+	}
+	cfg := &stconfig.Configuration{
+		Devices: []stconfig.DeviceConfiguration{
+			{DeviceID: okID, Name: "ok", MaxSendKbps: 200, MaxRecvKbps: 50},
+			{DeviceID: driftID, Name: "renamed", MaxSendKbps: 100},
+			{DeviceID: orphanID, Name: "orphan", MaxSendKbps: 100},
+		},
+		Folders: []stconfig.FolderConfiguration{
+			folder("ok", 60, "Kiosk ok"),
+			folder("drift", 3600, "Kiosk drift"),
+			folder("unmanaged", 3600, "Kiosk unmanaged"),
+		},
+	}
+	managed := state.Instance{
+		Devices: map[protocol.DeviceID]state.Device{
+			okID:     {Pattern: "kiosk", Name: "ok", Address: "10.0.0.1"},
+			driftID:  {Pattern: "kiosk", Name: "drift", Address: "10.0.0.2"},
+			orphanID: {Pattern: "removed", Name: "orphan"},
+		},
+		Folders: map[string]state.Folder{
+			"ok":    {Pattern: "kiosk", FolderPattern: "${name}", Device: okID},
+			"drift": {Pattern: "kiosk", FolderPattern: "${name}", Device: driftID},
+		},
+	}
+
+	res := findDrift(slog.Default(), cfg, managed, patterns, instance, time.Now())
+	if len(res) != 2 {
+		t.Fatalf("expected two drifted objects, got %+v", res)
+	}
+
+	dev := res[0]
+	if dev.Kind != "device" || dev.ID != driftID.String() || !slices.Equal(dev.Fields, []string{"maxSendKbps"}) {
+		t.Errorf("unexpected device drift %+v", dev.Drift)
+	}
+	if dev.device.MaxSendKbps != 200 || dev.device.Name != "renamed" {
+		t.Errorf("unexpected corrected device %+v", dev.device)
+	}
+
+	fld := res[1]
+	if fld.Kind != "folder" || fld.ID != "drift" || !slices.Equal(fld.Fields, []string{"rescanIntervalS"}) {
+		t.Errorf("unexpected folder drift %+v", fld.Drift)
+	}
+	if fld.folder.RescanIntervalS != 60 || fld.folder.Path != "/elsewhere/drift" || len(fld.folder.Devices) != 1 {
+		t.Errorf("unexpected corrected folder %+v", fld.folder)
+	}
+
+	// A window lifting the send limit is compared even though zero is the
+	// default
+	patterns.Pattern[0].BandwidthSchedule = []*config.BandwidthWindow{
+		{Start: "00:00", End: "00:00", MaxSendKbps: proto.Int32(0)},
+	}
+	res = findDrift(slog.Default(), cfg, managed, patterns, instance, time.Now())
+	if len(res) != 3 || res[0].ID != okID.String() || res[1].ID != driftID.String() {
+		t.Fatalf("expected both devices and the folder to drift, got %+v", res)
+	}
+	if dev := res[0]; !slices.Equal(dev.Fields, []string{"maxSendKbps"}) || dev.device.MaxSendKbps != 0 {
+		t.Errorf("unexpected device drift %+v", dev.Drift)
+	}
+}
@@ -5,11 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
+	stconfig "github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
 	"kastelo.dev/syncthing-configd/internal/api"
 	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/hooks"
+	"kastelo.dev/syncthing-configd/internal/state"
 )
 
 type EventListener struct {
@@ -17,14 +22,34 @@ type EventListener struct {
 	api        *api.API
 	patterns   *config.Configuration
 	eventTypes []events.EventType
+	hooks      *hooks.Runner
+	state      *state.Store
+	instance   *config.SyncthingInstance
+	peers      *Peers
+
+	deniedMut sync.Mutex
+	denied    map[protocol.DeviceID]time.Time // when on_deny last ran
 }
 
-func NewEventListener(log *slog.Logger, api *api.API, patterns *config.Configuration, eventTypes []events.EventType) *EventListener {
+// denyHookInterval is the minimum time between on_deny hooks for the same
+// device. Syncthing emits DeviceRejected on every connection attempt.
+const denyHookInterval = time.Hour
+
+// NewEventListener returns a listener for the given instance. Only the
+// patterns whose instance selector matches the instance are applied.
+// Accepted devices are propagated to the peers selected by the pattern;
+// peers may be nil.
+func NewEventListener(log *slog.Logger, api *api.API, patterns *config.Configuration, eventTypes []events.EventType, hooks *hooks.Runner, state *state.Store, instance *config.SyncthingInstance, peers *Peers) *EventListener {
 	return &EventListener{
 		log:        log.With("address", api.Address()),
 		api:        api,
 		patterns:   patterns,
 		eventTypes: eventTypes,
+		hooks:      hooks,
+		state:      state,
+		instance:   instance,
+		peers:      peers,
+		denied:     make(map[protocol.DeviceID]time.Time),
 	}
 }
 
@@ -43,6 +68,10 @@ func (s *EventListener) Serve(ctx context.Context) error {
 	}
 
 	s.log.Info("Connected to Syncthing", "version", ver.Version, "os", ver.OS, "arch", ver.Arch, "id", stat.MyID)
+	if err := s.api.SetIdentity(stat.MyID); err != nil {
+		s.log.Error("Not managing Syncthing instance until its new identity is accepted", "error", err)
+		return err
+	}
 
 	es := s.api.Events(s.eventTypes)
 
@@ -70,7 +99,8 @@ func (s *EventListener) Serve(ctx context.Context) error {
 					s.log.Error("Failed to process DeviceRejected event", "error", err)
 					continue
 				}
-				if err := s.handleDeviceRejected(data, s.patterns); err != nil {
+				data.instance = s.instance
+				if err := s.handleDeviceRejected(ctx, data, s.patterns); err != nil {
 					s.log.Error("Failed to process device", "error", err)
 				}
 			case events.FolderRejected:
@@ -84,29 +114,198 @@ func (s *EventListener) String() string {
 	return fmt.Sprintf("eventListener(%s)@%p", s.api.Address(), s)
 }
 
-func (s *EventListener) handleDeviceRejected(data *deviceRejectedData, cfg *config.Configuration) error {
+func (s *EventListener) handleDeviceRejected(ctx context.Context, data *deviceRejectedData, cfg *config.Configuration) error {
 	l := slog.With("device", data.device, "name", data.name, "address", data.address)
 
-	addDevice, addFolders, error := getDeviceRejectedConfigs(data, cfg)
+	payload := &hooks.Payload{
+		Instance: s.api.Address(),
+		Device:   data.device.String(),
+		Name:     data.name,
+		Address:  data.address.String(),
+	}
+
+	pat, addDevice, addFolders, error := getDeviceRejectedConfigs(data, cfg)
 	if error != nil {
 		if errors.Is(error, errNoMatchingPattern) {
 			l.Info("No matching pattern found")
+			s.deny(ctx, data.device, payload)
+			return nil
+		}
+		if errors.Is(error, errPathOutsideRoot) {
+			l.Warn("Not accepting device, folder path outside of allowed root", "error", error)
+			s.deny(ctx, data.device, payload)
 			return nil
 		}
 		return error
 	}
 
-	l.Info("Accepting device")
-	if err := s.api.SetDevice(addDevice); err != nil {
+	if pat.Placement != "" && s.peers != nil {
+		placed, err := s.peers.place(l, pat, data)
+		if err != nil {
+			return err
+		}
+		if placed != s {
+			// Accept the device as if it had been rejected there, unless
+			// it already was, so that hooks and propagation run once
+			placedCfg, err := placed.api.GetConfig()
+			if err != nil {
+				return err
+			}
+			if _, _, ok := placedCfg.Device(data.device); ok {
+				l.Debug("Device already accepted on the instance it was placed on", "instance", placed.api.Address())
+				return nil
+			}
+			l.Info("Device placed on another instance", "instance", placed.api.Address())
+			d := *data
+			d.instance = placed.instance
+			return placed.handleDeviceRejected(ctx, &d, placed.patterns)
+		}
+	}
+
+	patName := cfg.PatternName(pat)
+	payload.Pattern = patName
+
+	l.Info("Accepting device", "pattern", patName)
+	res, applyErr := s.applyPattern(l, pat, patName, data, addDevice, addFolders)
+	payload.Folders = res.folders
+	if applyErr != nil {
+		// The device wasn't fully accepted, so it's not an accept event.
+		// Whatever was added stays, and is retried on the next attempt.
+		l.Warn("Not running accept hook after failure", "error", applyErr)
+		return nil
+	}
+
+	payload.Event = hooks.EventAccept
+	if s.hooks.Hook(hooks.EventAccept).GetOnFailure() != config.HookFailurePolicy_FAILURE_ROLLBACK {
+		s.hooks.Go(ctx, payload)
+	} else if err := s.hooks.Run(ctx, payload); err != nil {
+		l.Warn("Rolling back accepted device after hook failure")
+		for _, id := range res.createdFolders {
+			if err := s.api.RemoveFolder(id); err != nil {
+				l.Error("Failed to remove folder during rollback", "folder", id, "error", err)
+				continue
+			}
+			if err := s.state.RemoveFolder(s.api.Identity(), id); err != nil {
+				l.Error("Failed to forget managed folder", "folder", id, "error", err)
+			}
+		}
+		if res.deviceCreated {
+			// Removing the device also removes it from any existing
+			// folders it was added to.
+			if err := s.api.RemoveDevice(addDevice.DeviceID); err != nil {
+				l.Error("Failed to remove device during rollback", "error", err)
+			} else if err := s.state.RemoveDevice(s.api.Identity(), addDevice.DeviceID); err != nil {
+				l.Error("Failed to forget managed device", "error", err)
+			}
+		}
+		return nil
+	}
+
+	// Only devices fully accepted here are propagated to other instances.
+	// This talks to each of them in turn, and shouldn't hold up events
+	// here.
+	go s.peers.propagate(l, s, pat, data)
+	return nil
+}
+
+// deny runs the on_deny hook for the device, unless it already ran for the
+// device within denyHookInterval.
+func (s *EventListener) deny(ctx context.Context, device protocol.DeviceID, payload *hooks.Payload) {
+	if !s.denyHookDue(device, time.Now()) {
+		return
+	}
+	payload.Event = hooks.EventDeny
+	s.hooks.Go(ctx, payload)
+}
+
+// denyHookDue returns true, and records the time, if the on_deny hook
+// hasn't run for the device within denyHookInterval of now.
+func (s *EventListener) denyHookDue(device protocol.DeviceID, now time.Time) bool {
+	s.deniedMut.Lock()
+	defer s.deniedMut.Unlock()
+	for id, t := range s.denied {
+		if now.Sub(t) >= denyHookInterval {
+			delete(s.denied, id)
+		}
+	}
+	if _, ok := s.denied[device]; ok {
+		return false
+	}
+	s.denied[device] = now
+	return true
+}
+
+// applied describes the changes made by applying a pattern.
+type applied struct {
+	deviceCreated  bool
+	createdFolders []string
+	folders        []string // shared with the device, created or not
+}
+
+// applyPattern adds the device and folders resulting from a pattern to the
+// instance and records them as managed. Failures are logged, and the
+// returned error is the first of them.
+func (s *EventListener) applyPattern(l *slog.Logger, pat *config.DevicePattern, patName string, data *deviceRejectedData, addDevice *stconfig.DeviceConfiguration, addFolders []*stconfig.FolderConfiguration) (applied, error) {
+	var res applied
+	var firstErr error
+	fail := func(err error) {
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	myID := s.api.Identity()
+	now := time.Now()
+
+	deviceCreated, err := s.api.SetDevice(addDevice)
+	if err != nil {
 		l.Error("Failed to add device", "error", err)
+		fail(fmt.Errorf("adding device: %w", err))
 	}
-	for _, fld := range addFolders {
+	if deviceCreated {
+		res.deviceCreated = true
+		dev := state.Device{
+			Pattern:  patName,
+			Name:     data.name,
+			Address:  data.address.String(),
+			Accepted: now,
+		}
+		if ttl := pat.TTL(); ttl > 0 {
+			dev.Expires = now.Add(ttl)
+		}
+		if err := s.state.AddDevice(myID, addDevice.DeviceID, dev); err != nil {
+			l.Error("Failed to record managed device", "error", err)
+		}
+	}
+	for i, fld := range addFolders {
 		l := l.With("folder", fld.ID)
+		if cd := pat.Folder[i].CreateDirectory; cd != nil {
+			if err := createFolderDirectory(fld, cd); err != nil {
+				l.Error("Failed to create folder directory", "path", fld.Path, "error", err)
+				fail(fmt.Errorf("folder %s: %w", fld.ID, err))
+				continue
+			}
+		}
 		l.Info("Accepting folder")
-		if err := s.api.SetFolder(fld); err != nil {
+		created, err := s.api.SetFolder(fld)
+		if err != nil {
 			l.Error("Failed to add folder", "error", err)
+			fail(fmt.Errorf("folder %s: %w", fld.ID, err))
+			continue
+		}
+		if created {
+			res.createdFolders = append(res.createdFolders, fld.ID)
+			f := state.Folder{
+				Pattern:       patName,
+				FolderPattern: pat.Folder[i].Id,
+				Device:        data.device,
+				Path:          fld.Path,
+				Created:       now,
+			}
+			if err := s.state.AddFolder(myID, fld.ID, f); err != nil {
+				l.Error("Failed to record managed folder", "error", err)
+			}
 		}
+		res.folders = append(res.folders, fld.ID)
 	}
-
-	return nil
+	return res, firstErr
 }
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"net/netip"
 	"os"
+	"time"
 
 	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/config"
 )
 
 var (
@@ -16,9 +18,11 @@ var (
 )
 
 type deviceRejectedData struct {
-	name    string
-	device  protocol.DeviceID
-	address netip.Addr
+	name     string
+	device   protocol.DeviceID
+	address  netip.Addr
+	time     time.Time
+	instance *config.SyncthingInstance // the instance the device connected to
 }
 
 func getDeviceRejectedData(ev events.Event) (*deviceRejectedData, error) {
@@ -27,7 +31,7 @@ func getDeviceRejectedData(ev events.Event) (*deviceRejectedData, error) {
 		return nil, errMalformedEvent
 	}
 
-	var res deviceRejectedData
+	res := deviceRejectedData{time: ev.Time}
 	if name, ok := dataMap["name"].(string); !ok {
 		return nil, errMalformedEvent
 	} else {
@@ -51,31 +55,66 @@ func getDeviceRejectedData(ev events.Event) (*deviceRejectedData, error) {
 	return &res, nil
 }
 
+// replaceVariables expands variables of the form ${name} or
+// ${name|filter|filter:arg} in the string. Unknown variables, as well as
+// variables that expand to an empty value, result in an error.
 func replaceVariables(s string, d *deviceRejectedData) (string, error) {
+	return expandVariables(s, d, nil)
+}
+
+// replacePathVariables is like replaceVariables, but sanitises each
+// expanded value so that it can be safely used as a single path component
+// or folder ID.
+func replacePathVariables(s string, d *deviceRejectedData, maxLen int) (string, error) {
+	return expandVariables(s, d, func(v string) string {
+		return sanitizePathComponent(v, maxLen)
+	})
+}
+
+func expandVariables(s string, d *deviceRejectedData, sanitize func(string) string) (string, error) {
 	var err error
-	res := os.Expand(s, func(key string) string {
-		switch key {
-		case "device":
-			v := d.device.String()
-			if v == "" {
-				err = fmt.Errorf("%w: %s", errBadExpansion, key)
-			}
-			return v
-		case "name":
-			v := d.name
-			if v == "" {
-				err = fmt.Errorf("%w: %s", errBadExpansion, key)
+	res := os.Expand(s, func(expr string) string {
+		v, verr := expandVariable(expr, d.variable)
+		if verr == nil && sanitize != nil {
+			if v = sanitize(v); v == "" {
+				verr = fmt.Errorf("%w: %s", errBadExpansion, expr)
 			}
-			return v
-		case "address":
-			v := d.address.String()
-			if v == "" {
-				err = fmt.Errorf("%w: %s", errBadExpansion, key)
-			}
-			return v
 		}
-		err = fmt.Errorf("%w: %s", errBadExpansion, key)
-		return ""
+		if verr != nil && err == nil {
+			err = verr
+		}
+		return v
 	})
 	return res, err
 }
+
+// variable returns the value of the named template variable, or false if
+// there is no such variable.
+func (d *deviceRejectedData) variable(key string) (string, bool) {
+	switch key {
+	case "device":
+		if d.device == protocol.EmptyDeviceID {
+			return "", true
+		}
+		return d.device.String(), true
+	case "name":
+		return d.name, true
+	case "address":
+		if !d.address.IsValid() {
+			return "", true
+		}
+		return d.address.String(), true
+	case "accepted":
+		if d.time.IsZero() {
+			return "", true
+		}
+		return d.time.UTC().Format(time.RFC3339), true
+	case "instance":
+		if d.instance == nil {
+			return "", true
+		}
+		return d.instance.DisplayName(), true
+	}
+	v, ok := d.instance.GetLabels()[key]
+	return v, ok
+}
@@ -3,8 +3,10 @@ package events
 import (
 	"net/netip"
 	"testing"
+	"time"
 
 	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/config"
 )
 
 func TestVariableExpansion(t *testing.T) {
@@ -15,6 +17,10 @@ func TestVariableExpansion(t *testing.T) {
 		name:    "test",
 		device:  protocol.LocalDeviceID,
 		address: netip.MustParseAddr("127.0.0.1"),
+		instance: &config.SyncthingInstance{
+			Name:   "hub1",
+			Labels: map[string]string{"tenant": "acme", "name": "ignored"},
+		},
 	}
 
 	cases := []struct {
@@ -47,6 +53,26 @@ func TestVariableExpansion(t *testing.T) {
 			data:  empty,
 			want:  "", // name is missing
 		},
+		{
+			input: "${tenant}/${name}",
+			data:  data,
+			want:  "acme/test", // built in variables take precedence
+		},
+		{
+			input: "${tenant}",
+			data:  empty,
+			want:  "", // tenant is missing
+		},
+		{
+			input: "${instance}/${name}",
+			data:  data,
+			want:  "hub1/test",
+		},
+		{
+			input: "${instance}",
+			data:  empty,
+			want:  "", // instance is missing
+		},
 	}
 
 	for _, c := range cases {
@@ -64,3 +90,24 @@ func TestVariableExpansion(t *testing.T) {
 		}
 	}
 }
+
+func TestDenyHookDue(t *testing.T) {
+	t.Parallel()
+
+	s := &EventListener{denied: make(map[protocol.DeviceID]time.Time)}
+	dev1, dev2 := protocol.DeviceID{1}, protocol.DeviceID{2}
+	t0 := time.Now()
+
+	if !s.denyHookDue(dev1, t0) {
+		t.Error("first rejection should run the hook")
+	}
+	if s.denyHookDue(dev1, t0.Add(time.Minute)) {
+		t.Error("repeated rejection should not run the hook")
+	}
+	if !s.denyHookDue(dev2, t0.Add(time.Minute)) {
+		t.Error("rejection of another device should run the hook")
+	}
+	if !s.denyHookDue(dev1, t0.Add(denyHookInterval)) {
+		t.Error("rejection after the interval should run the hook")
+	}
+}
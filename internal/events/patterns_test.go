@@ -13,6 +13,13 @@ func TestPatterns(t *testing.T) {
 
 	cfg := &config.Configuration{
 		Pattern: []*config.DevicePattern{
+			{
+				AcceptCidr:       []string{"127.0.0.0/8"},
+				InstanceSelector: map[string]string{"role": "archive"},
+				Folder: []*config.FolderPattern{
+					{Id: "archive"},
+				},
+			},
 			{
 				AcceptCidr: []string{"127.0.0.0/8"},
 				Folder: []*config.FolderPattern{
@@ -34,10 +41,14 @@ func TestPatterns(t *testing.T) {
 		},
 	}
 
+	archive := &config.SyncthingInstance{Labels: map[string]string{"role": "archive"}}
+	ingest := &config.SyncthingInstance{Labels: map[string]string{"role": "ingest"}}
+
 	cases := []struct {
-		address string
-		accept  bool
-		folders []string
+		address  string
+		instance *config.SyncthingInstance
+		accept   bool
+		folders  []string
 	}{
 		{
 			address: "127.2.3.4",
@@ -54,13 +65,26 @@ func TestPatterns(t *testing.T) {
 			accept:  false,
 			folders: nil,
 		},
+		{
+			address:  "127.2.3.4",
+			instance: archive,
+			accept:   true,
+			folders:  []string{"archive"},
+		},
+		{
+			address:  "127.2.3.4",
+			instance: ingest,
+			accept:   true,
+			folders:  []string{"test1"},
+		},
 	}
 
 	for _, c := range cases {
 		data := &deviceRejectedData{
-			address: netip.MustParseAddr(c.address),
+			address:  netip.MustParseAddr(c.address),
+			instance: c.instance,
 		}
-		device, folders, err := getDeviceRejectedConfigs(data, cfg)
+		_, device, folders, err := getDeviceRejectedConfigs(data, cfg)
 		if c.accept {
 			if err != nil {
 				t.Errorf("getDeviceRejectedConfigs(%q) returned error: %v", c.address, err)
@@ -0,0 +1,125 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+// place returns the listener for the instance that should accept the
+// device, among the connected instances the pattern applies to. The
+// decision is remembered, so that the device ends up on the same instance
+// however many of them reject it. A device already managed on one of the
+// instances, e.g. accepted before a restart, stays there. When placing a
+// device, the other instances are told to ignore it, and the chosen one to
+// stop ignoring it in case it was placed elsewhere before.
+func (p *Peers) place(l *slog.Logger, pat *config.DevicePattern, data *deviceRejectedData) (*EventListener, error) {
+	p.placeMut.Lock()
+	defer p.placeMut.Unlock()
+
+	var cands []*EventListener
+	for _, el := range p.selected(pat.InstanceSelector, nil) {
+		if el.api.Identity() != protocol.EmptyDeviceID {
+			cands = append(cands, el)
+		}
+	}
+	if len(cands) == 0 {
+		return nil, errNotConnected
+	}
+
+	if addr, ok := p.placements[data.device]; ok {
+		for _, el := range cands {
+			if el.api.Address() == addr {
+				return el, nil
+			}
+		}
+		// The instance is gone; place the device again
+	}
+
+	chosen, err := p.choose(pat, data, cands)
+	if err != nil {
+		return nil, err
+	}
+	p.placements[data.device] = chosen.api.Address()
+	l.Info("Placing device", "placement", pat.Placement, "instance", chosen.api.Address())
+
+	if unignored, err := chosen.api.UnignoreDevice(data.device); err != nil {
+		l.Warn("Failed to stop ignoring placed device", "instance", chosen.api.Address(), "error", err)
+	} else if unignored {
+		l.Info("No longer ignoring placed device", "instance", chosen.api.Address())
+	}
+	ignore := stconfig.ObservedDevice{
+		Time:    data.time,
+		ID:      data.device,
+		Name:    data.name,
+		Address: data.address.String(),
+	}
+	for _, el := range cands {
+		if el == chosen {
+			continue
+		}
+		if ignored, err := el.api.IgnoreDevice(ignore); err != nil {
+			l.Warn("Failed to ignore device placed elsewhere", "instance", el.api.Address(), "error", err)
+		} else if ignored {
+			l.Info("Ignoring device placed elsewhere", "instance", el.api.Address())
+		}
+	}
+	return chosen, nil
+}
+
+// choose returns the candidate the device is managed on, if any, or else
+// the one picked by the pattern's placement strategy.
+func (p *Peers) choose(pat *config.DevicePattern, data *deviceRejectedData, cands []*EventListener) (*EventListener, error) {
+	for _, el := range cands {
+		if _, ok := el.state.Device(el.api.Identity(), data.device); ok {
+			return el, nil
+		}
+	}
+
+	names := make([]string, len(cands))
+	loads := make([]int, len(cands))
+	for i, el := range cands {
+		names[i] = el.instance.DisplayName()
+		if pat.Placement == config.PlacementHash {
+			continue
+		}
+		cfg, err := el.api.GetConfig()
+		if err != nil {
+			return nil, fmt.Errorf("placement: %s: %w", el.api.Address(), err)
+		}
+		if pat.Placement == config.PlacementLeastFolders {
+			loads[i] = len(cfg.Folders)
+		} else {
+			loads[i] = len(cfg.Devices)
+		}
+	}
+	return cands[pickInstance(data.device, names, loads)], nil
+}
+
+// pickInstance returns the index of the instance with the lowest load. Ties
+// are broken by rendezvous hashing of the device ID and instance name, so
+// that with equal (or no) loads a device always maps to the same instance,
+// and only the devices of a removed instance move elsewhere.
+func pickInstance(dev protocol.DeviceID, names []string, loads []int) int {
+	best := 0
+	bestScore := rendezvousScore(dev, names[0])
+	for i := 1; i < len(names); i++ {
+		score := rendezvousScore(dev, names[i])
+		if loads[i] < loads[best] || loads[i] == loads[best] && score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+func rendezvousScore(dev protocol.DeviceID, name string) uint64 {
+	h := sha256.New()
+	h.Write(dev[:])
+	h.Write([]byte(name))
+	return binary.BigEndian.Uint64(h.Sum(nil))
+}
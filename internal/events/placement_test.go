@@ -0,0 +1,128 @@
+package events
+
+import (
+	"fmt"
+	"log/slog"
+	"testing"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api/apitest"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+func TestPickInstance(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"ingest-1", "ingest-2", "ingest-3"}
+
+	// With equal loads every instance gets a share of the devices, and a
+	// device always maps to the same instance
+	counts := make([]int, len(names))
+	for i := 0; i < 300; i++ {
+		dev := protocol.DeviceID{byte(i), byte(i >> 8), 42}
+		idx := pickInstance(dev, names, []int{0, 0, 0})
+		if again := pickInstance(dev, names, []int{0, 0, 0}); again != idx {
+			t.Fatalf("device %v placed on %d, then %d", dev, idx, again)
+		}
+		counts[idx]++
+	}
+	for i, c := range counts {
+		if c < 50 {
+			t.Errorf("instance %s got only %d of 300 devices", names[i], c)
+		}
+	}
+
+	// Removing an instance only moves the devices that were placed on it
+	for i := 0; i < 300; i++ {
+		dev := protocol.DeviceID{byte(i), byte(i >> 8), 42}
+		idx := pickInstance(dev, names, []int{0, 0, 0})
+		if idx == 2 {
+			continue
+		}
+		if got := pickInstance(dev, names[:2], []int{0, 0}); got != idx {
+			t.Errorf("device %v moved from %d to %d", dev, idx, got)
+		}
+	}
+
+	// The least loaded instance wins
+	dev := protocol.DeviceID{1, 2, 3}
+	if got := pickInstance(dev, names, []int{10, 3, 7}); got != 1 {
+		t.Errorf("picked %d, want the least loaded instance 1", got)
+	}
+}
+
+func TestPlaceManaged(t *testing.T) {
+	t.Parallel()
+
+	device := protocol.DeviceID{42}
+	store, err := state.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pat := &config.DevicePattern{
+		Name:             "ingest",
+		InstanceSelector: map[string]string{"role": "ingest"},
+		Placement:        config.PlacementHash,
+	}
+	patterns := &config.Configuration{Pattern: []*config.DevicePattern{pat}}
+
+	peers := NewPeers()
+	var sts []*apitest.Syncthing
+	var els []*EventListener
+	for i := 0; i < 2; i++ {
+		id := protocol.DeviceID{byte(i + 1)}
+		st, a := apitest.New(t, id, stconfig.Configuration{})
+		inst := &config.SyncthingInstance{Address: a.Address(), Name: fmt.Sprint("ingest-", i), Labels: map[string]string{"role": "ingest"}}
+		el := NewEventListener(slog.Default(), a, patterns, nil, nil, store, inst, peers)
+		if err := a.SetIdentity(id); err != nil {
+			t.Fatal(err)
+		}
+		peers.Add(el)
+		sts = append(sts, st)
+		els = append(els, el)
+	}
+
+	// The device is managed on the instance the hash wouldn't pick, as
+	// if it was placed there before a restart
+	other := els[1-pickInstance(device, []string{"ingest-0", "ingest-1"}, []int{0, 0})]
+	if err := store.AddDevice(other.api.Identity(), device, state.Device{Pattern: "ingest"}); err != nil {
+		t.Fatal(err)
+	}
+
+	placed, err := peers.place(slog.Default(), pat, &deviceRejectedData{device: device})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placed != other {
+		t.Errorf("device placed on %s, expected %s", placed.api.Address(), other.api.Address())
+	}
+
+	// The other instance ignores the device
+	for i, st := range sts {
+		ign := st.Config().IgnoredDevices
+		if els[i] == other && len(ign) != 0 || els[i] != other && (len(ign) != 1 || ign[0].ID != device) {
+			t.Errorf("instance %d: unexpected ignored devices %v", i, ign)
+		}
+	}
+
+	// When the chosen instance goes away, the device is placed on the
+	// remaining one, which stops ignoring it
+	peers.Remove(other)
+	if err := store.RemoveDevice(other.api.Identity(), device); err != nil {
+		t.Fatal(err)
+	}
+	placed, err = peers.place(slog.Default(), pat, &deviceRejectedData{device: device})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if placed == other {
+		t.Fatal("device placed on removed instance")
+	}
+	for i, st := range sts {
+		if els[i] == placed && len(st.Config().IgnoredDevices) != 0 {
+			t.Errorf("device still ignored on %s", placed.api.Address())
+		}
+	}
+}
@@ -0,0 +1,286 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/admin"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+var errNotConnected = errors.New("instance not connected")
+
+// Peers is the set of running event listeners, by instance address, used
+// to propagate accepted devices to sibling instances and to place devices
+// on one of several instances.
+type Peers struct {
+	mut       sync.Mutex
+	listeners map[string]*EventListener
+	status    map[string]*PropagationStatus
+
+	placeMut   sync.Mutex                   // serialises placement decisions
+	placements map[protocol.DeviceID]string // instance address, by device
+}
+
+// PropagationStatus counts the devices propagated to an instance, and the
+// failures doing so.
+type PropagationStatus struct {
+	Instance    string    `json:"instance"`
+	Propagated  int       `json:"propagated"`
+	Failed      int       `json:"failed"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastFailure time.Time `json:"lastFailure,omitempty"`
+}
+
+func NewPeers() *Peers {
+	return &Peers{
+		listeners:  make(map[string]*EventListener),
+		status:     make(map[string]*PropagationStatus),
+		placements: make(map[protocol.DeviceID]string),
+	}
+}
+
+func (p *Peers) Add(el *EventListener) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	p.listeners[el.api.Address()] = el
+}
+
+func (p *Peers) Remove(el *EventListener) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	if p.listeners[el.api.Address()] == el {
+		delete(p.listeners, el.api.Address())
+	}
+}
+
+// selected returns the listeners for the instances matching the selector,
+// except the given one.
+func (p *Peers) selected(selector map[string]string, except *EventListener) []*EventListener {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	var res []*EventListener
+	for _, el := range p.listeners {
+		if el != except && el.instance.Matches(selector) {
+			res = append(res, el)
+		}
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].api.Address() < res[b].api.Address()
+	})
+	return res
+}
+
+// propagate applies the pattern for the accepted device to the instances
+// selected by the pattern's propagate_to.
+func (p *Peers) propagate(l *slog.Logger, src *EventListener, pat *config.DevicePattern, data *deviceRejectedData) {
+	if p == nil || len(pat.PropagateTo) == 0 {
+		return
+	}
+	targets := p.selected(pat.PropagateTo, src)
+	if len(targets) == 0 {
+		return
+	}
+	self, err := src.selfDevice()
+	if err != nil {
+		// The device is still propagated, but the targets can't be
+		// told how to reach this instance if they don't know it.
+		l.Warn("Failed to get own device configuration", "error", err)
+	}
+	for _, dst := range targets {
+		l := l.With("target", dst.api.Address())
+		l.Info("Propagating device")
+		err := dst.acceptPropagated(l, self, pat, data)
+		if err != nil {
+			l.Warn("Failed to propagate device", "error", err)
+		}
+		p.record(dst.api.Address(), err)
+	}
+}
+
+func (p *Peers) record(instance string, err error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	st, ok := p.status[instance]
+	if !ok {
+		st = &PropagationStatus{Instance: instance}
+		p.status[instance] = st
+	}
+	if err != nil {
+		st.Failed++
+		st.LastError = err.Error()
+		st.LastFailure = time.Now()
+		return
+	}
+	st.Propagated++
+}
+
+func (p *Peers) statuses() []PropagationStatus {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	res := make([]PropagationStatus, 0, len(p.status))
+	for _, st := range p.status {
+		res = append(res, *st)
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Instance < res[b].Instance
+	})
+	return res
+}
+
+// Identity is the identity of an instance, as recorded when configd first
+// connected to it, and a different identity seen since, if any.
+type Identity struct {
+	Instance string            `json:"instance"`
+	ID       protocol.DeviceID `json:"id"`
+	Mismatch protocol.DeviceID `json:"mismatch"`
+}
+
+func (p *Peers) identities() []Identity {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	res := make([]Identity, 0, len(p.listeners))
+	for addr, el := range p.listeners {
+		id, mismatch := el.api.IdentityStatus()
+		res = append(res, Identity{Instance: addr, ID: id, Mismatch: mismatch})
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Instance < res[b].Instance
+	})
+	return res
+}
+
+// RegisterAdmin adds the propagation and identity endpoints to the admin
+// API.
+func (p *Peers) RegisterAdmin(srv *admin.Server) {
+	srv.Handle(http.MethodGet, "/rest/propagation", func(_ *http.Request) (any, error) {
+		return p.statuses(), nil
+	})
+	srv.Handle(http.MethodGet, "/rest/identity", func(_ *http.Request) (any, error) {
+		return p.identities(), nil
+	})
+	srv.Handle(http.MethodPost, "/rest/identity/accept", func(r *http.Request) (any, error) {
+		inst := r.URL.Query().Get("instance")
+		if inst == "" {
+			return nil, admin.BadRequest("missing instance")
+		}
+		p.mut.Lock()
+		el, ok := p.listeners[inst]
+		p.mut.Unlock()
+		if !ok {
+			return nil, admin.NotFound("no instance %s", inst)
+		}
+		_, mismatch := el.api.IdentityStatus()
+		if !el.api.AcceptIdentity() {
+			return nil, admin.Conflict("the identity of instance %s has not changed", inst)
+		}
+		el.log.Warn("Accepted new identity of Syncthing instance", "id", mismatch)
+		return Identity{Instance: inst, ID: mismatch}, nil
+	})
+}
+
+// acceptPropagated applies the pattern for a device accepted on another
+// instance. Variables are expanded for this instance, so that e.g.
+// ${instance} refers to the instance the device is propagated to. The
+// originating instance, if given, is added as a device here when it isn't
+// one already, and the folders are shared with it as well.
+func (s *EventListener) acceptPropagated(l *slog.Logger, from *stconfig.DeviceConfiguration, pat *config.DevicePattern, data *deviceRejectedData) error {
+	if s.api.Identity() == protocol.EmptyDeviceID {
+		return errNotConnected
+	}
+
+	d := *data
+	d.instance = s.instance
+	addDevice, addFolders, err := patternConfigs(pat, &d, s.patterns)
+	if err != nil {
+		return err
+	}
+
+	if from != nil && from.DeviceID != s.api.Identity() {
+		created, err := s.api.SetDevice(from)
+		if err != nil {
+			return fmt.Errorf("adding originating instance: %w", err)
+		}
+		if created {
+			l.Info("Added originating instance as device", "device", from.DeviceID, "addresses", from.Addresses)
+		}
+		for _, fld := range addFolders {
+			fld.Devices = append(fld.Devices, stconfig.FolderDeviceConfiguration{DeviceID: from.DeviceID})
+		}
+	}
+
+	_, err = s.applyPattern(l, pat, s.patterns.PatternName(pat), &d, addDevice, addFolders)
+	return err
+}
+
+// selfDevice returns a device configuration for this instance, as other
+// instances should have it: its own name, and addresses derived from its
+// listen addresses.
+func (s *EventListener) selfDevice() (*stconfig.DeviceConfiguration, error) {
+	myID := s.api.Identity()
+	if myID == protocol.EmptyDeviceID {
+		return nil, errNotConnected
+	}
+	cfg, err := s.api.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	dev := stconfig.DeviceConfiguration{DeviceID: myID}
+	if cur, _, ok := cfg.Device(myID); ok {
+		dev.Name = cur.Name
+	}
+	dev.Addresses = deviceAddresses(cfg.Options.RawListenAddresses, s.api.Address())
+	return &dev, nil
+}
+
+// deviceAddresses converts listen addresses to addresses other devices can
+// connect to. Unspecified listen hosts are replaced by the host of the API
+// address, when that is usable from elsewhere, and dynamic addresses are
+// always included.
+func deviceAddresses(listen []string, apiAddress string) []string {
+	host, _, err := net.SplitHostPort(apiAddress)
+	if ip := net.ParseIP(host); err != nil || host == "" || host == "localhost" || ip != nil && (ip.IsLoopback() || ip.IsUnspecified()) {
+		host = ""
+	}
+	if strings.HasPrefix(apiAddress, "unix://") {
+		host = ""
+	}
+
+	res := []string{"dynamic"}
+	seen := map[string]bool{"dynamic": true}
+	for _, addr := range listen {
+		if addr == "default" || strings.HasPrefix(addr, "dynamic") {
+			continue
+		}
+		u, err := url.Parse(addr)
+		if err != nil || u.Host == "" {
+			continue
+		}
+		lhost, port, err := net.SplitHostPort(u.Host)
+		if err != nil {
+			continue
+		}
+		if ip := net.ParseIP(lhost); lhost == "" || ip != nil && ip.IsUnspecified() {
+			if host == "" {
+				continue
+			}
+			lhost = host
+		}
+		u.Host = net.JoinHostPort(lhost, port)
+		if addr := u.String(); !seen[addr] {
+			seen[addr] = true
+			res = append(res, addr)
+		}
+	}
+	return res
+}
@@ -0,0 +1,169 @@
+package events
+
+import (
+	"errors"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/api/apitest"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+func TestPeers(t *testing.T) {
+	t.Parallel()
+
+	peers := NewPeers()
+	listener := func(addr string, labels map[string]string) *EventListener {
+		inst := &config.SyncthingInstance{Address: addr, ApiKey: "abc123", Labels: labels}
+		a, err := api.NewAPI(slog.Default(), inst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		el := NewEventListener(slog.Default(), a, &config.Configuration{}, nil, nil, nil, inst, peers)
+		peers.Add(el)
+		return el
+	}
+	active := listener("127.0.0.1:8081", map[string]string{"pair": "a", "role": "active"})
+	passive := listener("127.0.0.1:8082", map[string]string{"pair": "a", "role": "passive"})
+	other := listener("127.0.0.1:8083", map[string]string{"pair": "b", "role": "passive"})
+
+	sel := peers.selected(map[string]string{"pair": "a"}, active)
+	if len(sel) != 1 || sel[0] != passive {
+		t.Errorf("unexpected selection %v", sel)
+	}
+	sel = peers.selected(map[string]string{"role": "passive"}, active)
+	if len(sel) != 2 || sel[0] != passive || sel[1] != other {
+		t.Errorf("unexpected selection %v", sel)
+	}
+
+	peers.Remove(other)
+	sel = peers.selected(map[string]string{"role": "passive"}, active)
+	if len(sel) != 1 || sel[0] != passive {
+		t.Errorf("unexpected selection after removal %v", sel)
+	}
+
+	peers.record("127.0.0.1:8082", nil)
+	peers.record("127.0.0.1:8082", errNotConnected)
+	peers.record("127.0.0.1:8083", errors.New("boom"))
+	sts := peers.statuses()
+	if len(sts) != 2 {
+		t.Fatalf("expected two statuses, got %v", sts)
+	}
+	if sts[0].Propagated != 1 || sts[0].Failed != 1 || sts[0].LastError != errNotConnected.Error() {
+		t.Errorf("unexpected status %+v", sts[0])
+	}
+	if sts[1].Propagated != 0 || sts[1].Failed != 1 || sts[1].LastFailure.IsZero() {
+		t.Errorf("unexpected status %+v", sts[1])
+	}
+
+	// Propagating to a peer that hasn't connected yet fails without
+	// talking to it
+	pat := &config.DevicePattern{PropagateTo: map[string]string{"pair": "a"}}
+	peers.propagate(slog.Default(), active, pat, &deviceRejectedData{})
+	if sts := peers.statuses(); sts[0].Failed != 2 {
+		t.Errorf("unexpected status after propagation %+v", sts[0])
+	}
+}
+
+func TestPropagateAddsOrigin(t *testing.T) {
+	t.Parallel()
+
+	idA := protocol.DeviceID{1}
+	idB := protocol.DeviceID{2}
+	device := protocol.DeviceID{3}
+
+	_, apiA := apitest.New(t, idA, stconfig.Configuration{
+		Devices: []stconfig.DeviceConfiguration{{DeviceID: idA, Name: "a"}},
+		Options: stconfig.OptionsConfiguration{RawListenAddresses: []string{"default", "tcp://0.0.0.0:22000", "quic://192.0.2.1:22001"}},
+	})
+	stB, apiB := apitest.New(t, idB, stconfig.Configuration{
+		Devices: []stconfig.DeviceConfiguration{{DeviceID: idB, Name: "b"}},
+	})
+
+	store, err := state.Open(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pat := &config.DevicePattern{
+		Name:        "kiosk",
+		PropagateTo: map[string]string{"role": "passive"},
+		Folder: []*config.FolderPattern{
+			{Id: "${name}", Settings: &config.FolderConfiguration{Path: "/data/${name}"}},
+		},
+	}
+	patterns := &config.Configuration{Pattern: []*config.DevicePattern{pat}}
+
+	peers := NewPeers()
+	listener := func(a *api.API, id protocol.DeviceID, role string) *EventListener {
+		inst := &config.SyncthingInstance{Address: a.Address(), Labels: map[string]string{"role": role}}
+		el := NewEventListener(slog.Default(), a, patterns, nil, nil, store, inst, peers)
+		if err := a.SetIdentity(id); err != nil {
+			t.Fatal(err)
+		}
+		peers.Add(el)
+		return el
+	}
+	active := listener(apiA, idA, "active")
+	listener(apiB, idB, "passive")
+
+	peers.propagate(slog.Default(), active, pat, &deviceRejectedData{name: "kiosk1", device: device})
+	if sts := peers.statuses(); len(sts) != 1 || sts[0].Propagated != 1 {
+		t.Fatalf("unexpected status %+v", sts)
+	}
+
+	// The originating instance is now a device on the target, reachable
+	// on its listen addresses, and the folder is shared with it. The
+	// unspecified listen address is dropped, as the API address is
+	// loopback.
+	cfg := stB.Config()
+	dev, _, ok := cfg.Device(idA)
+	if !ok {
+		t.Fatal("originating instance not added")
+	}
+	want := []string{"dynamic", "quic://192.0.2.1:22001"}
+	if dev.Name != "a" || !slices.Equal(dev.Addresses, want) {
+		t.Errorf("unexpected device %s %v, expected %v", dev.Name, dev.Addresses, want)
+	}
+	fld, _, ok := cfg.Folder("kiosk1")
+	if !ok {
+		t.Fatal("folder not created")
+	}
+	var shared []protocol.DeviceID
+	for _, d := range fld.Devices {
+		shared = append(shared, d.DeviceID)
+	}
+	if !slices.Contains(shared, idA) || !slices.Contains(shared, device) {
+		t.Errorf("folder not shared with origin and device: %v", shared)
+	}
+}
+
+func TestDeviceAddresses(t *testing.T) {
+	t.Parallel()
+
+	listen := []string{"default", "tcp://0.0.0.0:22000", "tcp://:22000", "quic://[::]:22000", "tcp://192.0.2.1:22000", "dynamic+https://relays.syncthing.net/endpoint"}
+	cases := []struct {
+		api  string
+		want []string
+	}{
+		{"st1.example.com:8384", []string{"dynamic", "tcp://st1.example.com:22000", "quic://st1.example.com:22000", "tcp://192.0.2.1:22000"}},
+		{"[2001:db8::1]:8384", []string{"dynamic", "tcp://[2001:db8::1]:22000", "quic://[2001:db8::1]:22000", "tcp://192.0.2.1:22000"}},
+		{"127.0.0.1:8384", []string{"dynamic", "tcp://192.0.2.1:22000"}},
+		{"localhost:8384", []string{"dynamic", "tcp://192.0.2.1:22000"}},
+		{"unix:///run/syncthing/gui.sock", []string{"dynamic", "tcp://192.0.2.1:22000"}},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.api, func(t *testing.T) {
+			t.Parallel()
+			if got := deviceAddresses(listen, tc.api); !slices.Equal(got, tc.want) {
+				t.Errorf("got %v, expected %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+const defaultMaxVariableLength = 64
+
+var errPathOutsideRoot = errors.New("path is outside of the allowed root")
+
+// sanitizePathComponent makes a variable value safe to use as a single
+// path component or folder ID. Path separators and control characters are
+// removed, as are leading dots and surrounding whitespace, so that the
+// result can neither traverse directories nor become a hidden file. The
+// result is truncated to at most maxLen characters (a default is used when
+// maxLen is zero or negative).
+func sanitizePathComponent(s string, maxLen int) string {
+	if maxLen <= 0 {
+		maxLen = defaultMaxVariableLength
+	}
+
+	res := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || unicode.IsControl(r) || r == unicode.ReplacementChar {
+			return -1
+		}
+		return r
+	}, s)
+	// Trim until stable, as removing whitespace can expose more dots
+	// (" .." or ". .").
+	for {
+		trimmed := strings.TrimSpace(strings.TrimLeft(res, "."))
+		if trimmed == res {
+			break
+		}
+		res = trimmed
+	}
+
+	return prefix(res, maxLen)
+}
+
+// checkPathRoot returns an error if the path is not inside the given root
+// directory. An empty root allows any path.
+func checkPathRoot(path, root string) error {
+	if root == "" || path == "" {
+		return nil
+	}
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("%w: %s is not absolute", errPathOutsideRoot, path)
+	}
+	if !config.PathInside(path, root) {
+		return fmt.Errorf("%w: %s is not inside %s", errPathOutsideRoot, path, root)
+	}
+	return nil
+}
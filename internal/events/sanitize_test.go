@@ -0,0 +1,107 @@
+package events
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+func TestSanitizePathComponent(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		input  string
+		maxLen int
+		want   string
+	}{
+		{"laptop", 0, "laptop"},
+		{"../../etc", 0, "etc"},
+		{"..", 0, ""},
+		{" ..", 0, ""},
+		{". .", 0, ""},
+		{" . . /etc", 0, "etc"},
+		{"\t..\n..", 0, ""},
+		{".hidden", 0, "hidden"},
+		{`a\b/c`, 0, "abc"},
+		{"tab\there\x00", 0, "tabhere"},
+		{"Jakob's laptop", 0, "Jakob's laptop"},
+		{strings.Repeat("x", 100), 0, strings.Repeat("x", defaultMaxVariableLength)},
+		{"abcdef", 3, "abc"},
+	}
+
+	for _, c := range cases {
+		if got := sanitizePathComponent(c.input, c.maxLen); got != c.want {
+			t.Errorf("sanitizePathComponent(%q, %d) = %q, want %q", c.input, c.maxLen, got, c.want)
+		}
+	}
+}
+
+func TestCheckPathRoot(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		path string
+		root string
+		ok   bool
+	}{
+		{"/var/device-folders/foo", "", true},
+		{"", "/var/device-folders", true},
+		{"/var/device-folders/foo", "/var/device-folders", true},
+		{"/var/device-folders/foo/bar", "/var/device-folders/", true},
+		{"/var/device-folders", "/var/device-folders", false},
+		{"/var/device-folders/../etc", "/var/device-folders", false},
+		{"/var/device-folders-other/foo", "/var/device-folders", false},
+		{"relative/foo", "/var/device-folders", false},
+	}
+
+	for _, c := range cases {
+		err := checkPathRoot(c.path, c.root)
+		if c.ok && err != nil {
+			t.Errorf("checkPathRoot(%q, %q) returned error: %v", c.path, c.root, err)
+		} else if !c.ok && !errors.Is(err, errPathOutsideRoot) {
+			t.Errorf("checkPathRoot(%q, %q) = %v, want errPathOutsideRoot", c.path, c.root, err)
+		}
+	}
+}
+
+func TestPatternPathTraversal(t *testing.T) {
+	t.Parallel()
+
+	cfg := &config.Configuration{
+		Pattern: []*config.DevicePattern{
+			{
+				AcceptCidr: []string{"127.0.0.0/8"},
+				PathRoot:   "/var/device-folders",
+				Folder: []*config.FolderPattern{
+					{
+						Id: "${name}",
+						Settings: &config.FolderConfiguration{
+							Path: "/var/device-folders/${name}",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	data := &deviceRejectedData{
+		name:    "../../etc",
+		address: netip.MustParseAddr("127.0.0.1"),
+	}
+	_, _, folders, err := getDeviceRejectedConfigs(data, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if folders[0].ID != "etc" || folders[0].Path != "/var/device-folders/etc" {
+		t.Errorf("got folder %q at %q, want sanitised values", folders[0].ID, folders[0].Path)
+	}
+
+	// A path template that escapes the root on its own is rejected.
+	cfg.Pattern[0].Folder[0].Settings.Path = "/var/${name}"
+	if _, _, _, err := getDeviceRejectedConfigs(data, cfg); !errors.Is(err, errPathOutsideRoot) {
+		t.Errorf("got error %v, want errPathOutsideRoot", err)
+	}
+}
@@ -0,0 +1,139 @@
+package events
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var errBadFilter = errors.New("failed to apply filter")
+
+// A filter transforms a variable value. The argument is whatever follows
+// the colon in the filter expression, e.g. "24" for "subnet:24", or the
+// empty string if there was no argument.
+type filter func(value, arg string) (string, error)
+
+var filters = map[string]filter{
+	"lower": func(v, _ string) (string, error) {
+		return strings.ToLower(v), nil
+	},
+	"upper": func(v, _ string) (string, error) {
+		return strings.ToUpper(v), nil
+	},
+	"slug": func(v, _ string) (string, error) {
+		return slug(v), nil
+	},
+	"short": func(v, _ string) (string, error) {
+		return prefix(v, 7), nil
+	},
+	"prefix": func(v, arg string) (string, error) {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("prefix: bad length %q", arg)
+		}
+		return prefix(v, n), nil
+	},
+	"sha256": func(v, _ string) (string, error) {
+		hash := sha256.Sum256([]byte(v))
+		return hex.EncodeToString(hash[:]), nil
+	},
+	"subnet": func(v, arg string) (string, error) {
+		bits, err := strconv.Atoi(arg)
+		if err != nil {
+			return "", fmt.Errorf("subnet: bad prefix length %q", arg)
+		}
+		addr, err := netip.ParseAddr(v)
+		if err != nil {
+			return "", fmt.Errorf("subnet: %w", err)
+		}
+		pref, err := addr.Prefix(bits)
+		if err != nil {
+			return "", fmt.Errorf("subnet: %w", err)
+		}
+		return pref.Addr().String(), nil
+	},
+	"default": func(v, arg string) (string, error) {
+		if v == "" {
+			return arg, nil
+		}
+		return v, nil
+	},
+	"date": func(v, arg string) (string, error) {
+		if v == "" {
+			return "", nil
+		}
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return "", fmt.Errorf("date: %w", err)
+		}
+		if arg == "" {
+			arg = time.DateOnly
+		}
+		return t.Format(arg), nil
+	},
+}
+
+// expandVariable expands a single variable expression of the form
+// "name|filter|filter:arg", looking up the variable using the given
+// function. An unknown variable or filter, or a resulting empty value, is
+// an error.
+func expandVariable(expr string, lookup func(string) (string, bool)) (string, error) {
+	parts := strings.Split(expr, "|")
+	key := strings.TrimSpace(parts[0])
+	value, ok := lookup(key)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errBadExpansion, key)
+	}
+
+	for _, f := range parts[1:] {
+		name, arg, _ := strings.Cut(f, ":")
+		name = strings.TrimSpace(name)
+		fn, ok := filters[name]
+		if !ok {
+			return "", fmt.Errorf("%w: %s: unknown filter %q", errBadFilter, key, name)
+		}
+		var err error
+		value, err = fn(value, arg)
+		if err != nil {
+			return "", fmt.Errorf("%w: %s: %w", errBadFilter, key, err)
+		}
+	}
+
+	if value == "" {
+		return "", fmt.Errorf("%w: %s", errBadExpansion, key)
+	}
+	return value, nil
+}
+
+func prefix(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n])
+}
+
+// slug returns a lower case version of the string with every run of
+// characters other than letters and digits replaced by a single dash.
+func slug(s string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && sb.Len() > 0 {
+				sb.WriteRune('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return sb.String()
+}
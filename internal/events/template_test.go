@@ -0,0 +1,118 @@
+package events
+
+import (
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestTemplateFilters(t *testing.T) {
+	t.Parallel()
+
+	empty := &deviceRejectedData{}
+	data := &deviceRejectedData{
+		name:    "Kiosk #12 (Lobby)",
+		device:  protocol.LocalDeviceID,
+		address: netip.MustParseAddr("172.16.32.17"),
+		time:    time.Date(2024, 8, 15, 12, 30, 0, 0, time.UTC),
+	}
+
+	cases := []struct {
+		input string
+		data  *deviceRejectedData
+		want  string // empty means error
+	}{
+		{
+			input: "${name|lower}",
+			data:  data,
+			want:  "kiosk #12 (lobby)",
+		},
+		{
+			input: "${name|upper}",
+			data:  data,
+			want:  "KIOSK #12 (LOBBY)",
+		},
+		{
+			input: "${name|slug}",
+			data:  data,
+			want:  "kiosk-12-lobby",
+		},
+		{
+			input: "${device|short}",
+			data:  data,
+			want:  "7777777",
+		},
+		{
+			input: "${address|subnet:24}",
+			data:  data,
+			want:  "172.16.32.0",
+		},
+		{
+			input: "/data/${device|sha256|prefix:2}/${device|short}",
+			data:  data,
+			want:  "/data/72/7777777",
+		},
+		{
+			input: "${name|default:unknown}",
+			data:  empty,
+			want:  "unknown",
+		},
+		{
+			input: "${name|default:unknown}",
+			data:  data,
+			want:  "Kiosk #12 (Lobby)",
+		},
+		{
+			input: "${accepted|date}",
+			data:  data,
+			want:  "2024-08-15",
+		},
+		{
+			input: "${accepted|date:2006/01}",
+			data:  data,
+			want:  "2024/08",
+		},
+		{
+			input: "${accepted}",
+			data:  empty,
+			want:  "", // no acceptance time
+		},
+		{
+			input: "${name|nonexistent}",
+			data:  data,
+			want:  "", // unknown filter
+		},
+		{
+			input: "${address|subnet:abc}",
+			data:  data,
+			want:  "", // bad argument
+		},
+		{
+			input: "${name|slug}",
+			data:  &deviceRejectedData{name: "!!!"},
+			want:  "", // empty after filtering
+		},
+		{
+			input: "${foo|default:bar}",
+			data:  data,
+			want:  "", // foo is unknown
+		},
+	}
+
+	for _, c := range cases {
+		got, err := replaceVariables(c.input, c.data)
+		if c.want != "" {
+			if err != nil {
+				t.Errorf("replaceVariables(%q, %v) returned error: %v", c.input, c.data, err)
+				continue
+			}
+			if got != c.want {
+				t.Errorf("replaceVariables(%q, %v) = %q, want %q", c.input, c.data, got, c.want)
+			}
+		} else if err == nil {
+			t.Errorf("replaceVariables(%q, %v) = %q, want error", c.input, c.data, got)
+		}
+	}
+}
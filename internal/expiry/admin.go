@@ -0,0 +1,100 @@
+package expiry
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/admin"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+// Expiry is a managed device that expires.
+type Expiry struct {
+	Instance protocol.DeviceID `json:"instance"`
+	Device   protocol.DeviceID `json:"device"`
+	Name     string            `json:"name,omitempty"`
+	Pattern  string            `json:"pattern"`
+	Expires  time.Time         `json:"expires"`
+	Paused   bool              `json:"paused,omitempty"`
+}
+
+// List returns the expiring devices of all instances, soonest first.
+func List(store *state.Store) []Expiry {
+	res := []Expiry{}
+	for instID, inst := range store.Instances() {
+		for id, dev := range inst.Devices {
+			if dev.Expires.IsZero() {
+				continue
+			}
+			res = append(res, Expiry{
+				Instance: instID,
+				Device:   id,
+				Name:     dev.Name,
+				Pattern:  dev.Pattern,
+				Expires:  dev.Expires,
+				Paused:   dev.Paused,
+			})
+		}
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Expires.Before(res[b].Expires)
+	})
+	return res
+}
+
+// Extend postpones the expiry of the device, on every instance where it
+// expires, by the given duration. An expiry that has already passed is
+// extended from now. It returns false if the device doesn't expire
+// anywhere.
+func Extend(store *state.Store, device protocol.DeviceID, by time.Duration, now time.Time) (bool, error) {
+	found := false
+	for instID, inst := range store.Instances() {
+		if dev, ok := inst.Devices[device]; !ok || dev.Expires.IsZero() {
+			continue
+		}
+		_, err := store.UpdateDevice(instID, device, func(d *state.Device) {
+			if d.Expires.Before(now) {
+				d.Expires = now
+			}
+			d.Expires = d.Expires.Add(by)
+		})
+		if err != nil {
+			return found, err
+		}
+		found = true
+	}
+	return found, nil
+}
+
+// RegisterAdmin adds the expiry endpoints to the admin API.
+func RegisterAdmin(srv *admin.Server, store *state.Store) {
+	srv.Handle(http.MethodGet, "/rest/expiry", func(_ *http.Request) (any, error) {
+		return List(store), nil
+	})
+	srv.Handle(http.MethodPost, "/rest/expiry/extend", func(r *http.Request) (any, error) {
+		device, err := protocol.DeviceIDFromString(r.URL.Query().Get("device"))
+		if err != nil {
+			return nil, admin.BadRequest("device: %v", err)
+		}
+		by, err := time.ParseDuration(r.URL.Query().Get("by"))
+		if err != nil || by <= 0 {
+			return nil, admin.BadRequest("by: expected a positive duration like 24h")
+		}
+		found, err := Extend(store, device, by, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, admin.NotFound("device %s does not expire", device)
+		}
+		var res []Expiry
+		for _, exp := range List(store) {
+			if exp.Device == device {
+				res = append(res, exp)
+			}
+		}
+		return res, nil
+	})
+}
@@ -0,0 +1,198 @@
+// Package expiry removes or pauses devices accepted by patterns with a
+// time to live, once it has passed.
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+const checkInterval = time.Minute
+
+type Expirer struct {
+	log   *slog.Logger
+	api   *api.API
+	cfg   *config.Configuration
+	state *state.Store
+}
+
+func New(log *slog.Logger, api *api.API, cfg *config.Configuration, state *state.Store) *Expirer {
+	return &Expirer{
+		log:   log.With("address", api.Address()),
+		api:   api,
+		cfg:   cfg,
+		state: state,
+	}
+}
+
+// Serve checks for expired devices every minute, so that changes to the
+// expiry through the admin API take effect promptly.
+func (e *Expirer) Serve(ctx context.Context) error {
+	for {
+		if err := e.run(time.Now()); err != nil {
+			e.log.Error("Failed to expire devices", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(checkInterval):
+		}
+	}
+}
+
+func (e *Expirer) String() string {
+	return fmt.Sprintf("expirer(%s)@%p", e.api.Address(), e)
+}
+
+func (e *Expirer) run(now time.Time) error {
+	status, err := e.api.GetSystemStatus()
+	if err != nil {
+		return err
+	}
+	myID := status.MyID
+	if err := e.api.VerifyIdentity(myID); err != nil {
+		return err
+	}
+	managed := e.state.Instance(myID)
+
+	acts := actions(managed, e.cfg, now)
+	if len(acts) == 0 {
+		return nil
+	}
+	cfg, err := e.api.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	for _, act := range acts {
+		l := e.log.With("device", act.id, "name", managed.Devices[act.id].Name, "expires", managed.Devices[act.id].Expires)
+		switch act.kind {
+		case actionRemove:
+			l.Info("Removing expired device")
+			for _, fld := range act.folders {
+				if sharedWithOthers(cfg, fld, myID, act.id) {
+					l.Info("Keeping folder shared with other devices", "folder", fld)
+					continue
+				}
+				if err := e.api.RemoveFolder(fld); err != nil {
+					l.Error("Failed to remove folder", "folder", fld, "error", err)
+					continue
+				}
+				if err := e.state.RemoveFolder(myID, fld); err != nil {
+					l.Error("Failed to forget managed folder", "folder", fld, "error", err)
+				}
+			}
+			if err := e.api.RemoveDevice(act.id); err != nil {
+				l.Error("Failed to remove device", "error", err)
+				continue
+			}
+			if err := e.state.RemoveDevice(myID, act.id); err != nil {
+				l.Error("Failed to forget managed device", "error", err)
+			}
+			// Otherwise the device is accepted again the next time it
+			// connects
+			dev := managed.Devices[act.id]
+			ignore := stconfig.ObservedDevice{Time: now, ID: act.id, Name: dev.Name, Address: dev.Address}
+			if _, err := e.api.IgnoreDevice(ignore); err != nil {
+				l.Error("Failed to ignore expired device", "error", err)
+			}
+
+		case actionPause, actionResume:
+			paused := act.kind == actionPause
+			if paused {
+				l.Info("Pausing expired device")
+			} else {
+				l.Info("Resuming device with extended expiry")
+			}
+			err := e.api.InConfigTx(func(tx *api.ConfigTx) error {
+				return tx.PatchDevice(act.id, map[string]any{"paused": paused})
+			})
+			if err != nil {
+				l.Error("Failed to pause or resume device", "error", err)
+				continue
+			}
+			if _, err := e.state.UpdateDevice(myID, act.id, func(d *state.Device) { d.Paused = paused }); err != nil {
+				l.Error("Failed to record paused device", "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+type actionKind int
+
+const (
+	actionRemove actionKind = iota
+	actionPause
+	actionResume
+)
+
+type action struct {
+	kind    actionKind
+	id      protocol.DeviceID
+	folders []string // created for the device, for actionRemove
+}
+
+// actions returns what to do with the managed devices at now. Expired
+// devices are removed or paused, as set by their pattern. Devices whose
+// pattern is gone, and protected devices, are left alone, as are
+// protected folders of removed devices. A device paused on expiry is
+// resumed if its expiry has been extended.
+func actions(managed state.Instance, patterns *config.Configuration, now time.Time) []action {
+	var res []action
+	for id, dev := range managed.Devices {
+		if dev.Expires.IsZero() {
+			continue
+		}
+		pat := patterns.PatternByName(dev.Pattern)
+		if pat == nil {
+			continue
+		}
+		expired := !now.Before(dev.Expires) && !patterns.IsProtected(id.String())
+		pause := pat.GetExpireAction() == config.ExpireActionPause
+		switch {
+		case expired && !pause:
+			act := action{kind: actionRemove, id: id}
+			for fid, fld := range managed.Folders {
+				if fld.Device == id && !patterns.IsProtected(fid) {
+					act.folders = append(act.folders, fid)
+				}
+			}
+			sort.Strings(act.folders)
+			res = append(res, act)
+		case expired && !dev.Paused:
+			res = append(res, action{kind: actionPause, id: id})
+		case !expired && dev.Paused:
+			res = append(res, action{kind: actionResume, id: id})
+		}
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].id.Compare(res[b].id) < 0
+	})
+	return res
+}
+
+// sharedWithOthers returns true if the folder is shared with any device
+// other than the instance itself and the expired device.
+func sharedWithOthers(cfg *stconfig.Configuration, folderID string, myID, id protocol.DeviceID) bool {
+	fld, _, ok := cfg.Folder(folderID)
+	if !ok {
+		return false
+	}
+	for _, dev := range fld.Devices {
+		if dev.DeviceID != myID && dev.DeviceID != id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package expiry
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api/apitest"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+func TestActions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)
+	removeID := protocol.DeviceID{1}
+	pauseID := protocol.DeviceID{2}
+	pausedID := protocol.DeviceID{3}
+	extendedID := protocol.DeviceID{4}
+	validID := protocol.DeviceID{5}
+	permanentID := protocol.DeviceID{6}
+	orphanID := protocol.DeviceID{7}
+	protectedID := protocol.DeviceID{8}
+	declaredID := protocol.DeviceID{9}
+
+	patterns := &config.Configuration{
+		Pattern: []*config.DevicePattern{
+			{Name: "tech", Ttl: "72h"},
+			{Name: "visitor", Ttl: "8h", ExpireAction: config.ExpireActionPause},
+		},
+		GarbageCollect: &config.GarbageCollection{Protect: []string{protectedID.String(), "tech-keep-*"}},
+		Device:         []*config.DesiredDevice{{Id: declaredID.String()}},
+	}
+	managed := state.Instance{
+		Devices: map[protocol.DeviceID]state.Device{
+			removeID:    {Pattern: "tech", Expires: now},
+			pauseID:     {Pattern: "visitor", Expires: now.Add(-time.Hour)},
+			pausedID:    {Pattern: "visitor", Expires: now.Add(-time.Hour), Paused: true},
+			extendedID:  {Pattern: "visitor", Expires: now.Add(time.Hour), Paused: true},
+			validID:     {Pattern: "tech", Expires: now.Add(time.Hour)},
+			permanentID: {Pattern: "tech"},
+			orphanID:    {Pattern: "removed", Expires: now.Add(-time.Hour)},
+			protectedID: {Pattern: "tech", Expires: now.Add(-time.Hour)},
+			declaredID:  {Pattern: "visitor", Expires: now.Add(-time.Hour)},
+		},
+		Folders: map[string]state.Folder{
+			"tech-b":      {Device: removeID},
+			"tech-a":      {Device: removeID},
+			"tech-keep-a": {Device: removeID},
+			"other":       {Device: validID},
+		},
+	}
+
+	acts := actions(managed, patterns, now)
+	want := []action{
+		{kind: actionRemove, id: removeID, folders: []string{"tech-a", "tech-b"}},
+		{kind: actionPause, id: pauseID},
+		{kind: actionResume, id: extendedID},
+	}
+	if len(acts) != len(want) {
+		t.Fatalf("got actions %+v, want %+v", acts, want)
+	}
+	for i := range want {
+		if acts[i].kind != want[i].kind || acts[i].id != want[i].id || !slices.Equal(acts[i].folders, want[i].folders) {
+			t.Errorf("action %d: got %+v, want %+v", i, acts[i], want[i])
+		}
+	}
+}
+
+func TestExtend(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)
+	inst1 := protocol.DeviceID{1}
+	inst2 := protocol.DeviceID{2}
+	dev := protocol.DeviceID{3}
+	permanent := protocol.DeviceID{4}
+
+	store, err := state.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = store.AddDevice(inst1, dev, state.Device{Pattern: "tech", Expires: now.Add(time.Hour)})
+	_ = store.AddDevice(inst2, dev, state.Device{Pattern: "tech", Expires: now.Add(-time.Hour), Paused: true})
+	_ = store.AddDevice(inst1, permanent, state.Device{Pattern: "other"})
+
+	if found, err := Extend(store, dev, 24*time.Hour, now); !found || err != nil {
+		t.Fatal("extending:", found, err)
+	}
+	if found, _ := Extend(store, permanent, 24*time.Hour, now); found {
+		t.Error("extended a device without expiry")
+	}
+
+	exps := List(store)
+	if len(exps) != 2 {
+		t.Fatalf("expected two expiring devices, got %+v", exps)
+	}
+	// Sorted soonest first; the passed expiry is extended from now
+	if exps[0].Instance != inst2 || !exps[0].Expires.Equal(now.Add(24*time.Hour)) || !exps[0].Paused {
+		t.Errorf("unexpected expiry %+v", exps[0])
+	}
+	if exps[1].Instance != inst1 || !exps[1].Expires.Equal(now.Add(25*time.Hour)) {
+		t.Errorf("unexpected expiry %+v", exps[1])
+	}
+}
+
+func TestRunRemove(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)
+	myID := protocol.DeviceID{1}
+	dev := protocol.DeviceID{2}
+
+	st, a := apitest.New(t, myID, stconfig.Configuration{
+		Devices: []stconfig.DeviceConfiguration{{DeviceID: myID}, {DeviceID: dev, Name: "tech1"}},
+		Folders: []stconfig.FolderConfiguration{
+			{ID: "tech1", Devices: []stconfig.FolderDeviceConfiguration{{DeviceID: myID}, {DeviceID: dev}}},
+			{ID: "keep-tech1", Devices: []stconfig.FolderDeviceConfiguration{{DeviceID: myID}, {DeviceID: dev}}},
+		},
+	})
+	store, err := state.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = store.AddDevice(myID, dev, state.Device{Pattern: "tech", Name: "tech1", Address: "192.0.2.1", Expires: now})
+	_ = store.AddFolder(myID, "tech1", state.Folder{Pattern: "tech", Device: dev})
+	_ = store.AddFolder(myID, "keep-tech1", state.Folder{Pattern: "tech", Device: dev})
+	cfg := &config.Configuration{
+		Pattern:        []*config.DevicePattern{{Name: "tech", Ttl: "72h"}},
+		GarbageCollect: &config.GarbageCollection{Protect: []string{"keep-*"}},
+	}
+
+	if err := New(slog.Default(), a, cfg, store).run(now); err != nil {
+		t.Fatal(err)
+	}
+
+	// The device and its folder are gone, except for the protected one,
+	// and the device is ignored so that it isn't accepted again
+	stcfg := st.Config()
+	if _, _, ok := stcfg.Device(dev); ok {
+		t.Error("device not removed")
+	}
+	if _, _, ok := stcfg.Folder("tech1"); ok {
+		t.Error("folder not removed")
+	}
+	if _, _, ok := stcfg.Folder("keep-tech1"); !ok {
+		t.Error("protected folder removed")
+	}
+	if ign := stcfg.IgnoredDevices; len(ign) != 1 || ign[0].ID != dev || ign[0].Name != "tech1" || ign[0].Address != "192.0.2.1" {
+		t.Errorf("unexpected ignored devices %+v", ign)
+	}
+	if _, ok := store.Device(myID, dev); ok {
+		t.Error("device still recorded")
+	}
+}
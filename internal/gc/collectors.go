@@ -0,0 +1,116 @@
+package gc
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+
+	"kastelo.dev/syncthing-configd/internal/admin"
+)
+
+// Collectors is the set of running garbage collectors, by instance
+// address, for use by the admin API.
+type Collectors struct {
+	mut sync.Mutex
+	gcs map[string]*GarbageCollector
+}
+
+func NewCollectors() *Collectors {
+	return &Collectors{gcs: make(map[string]*GarbageCollector)}
+}
+
+func (c *Collectors) Add(gc *GarbageCollector) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.gcs[gc.api.Address()] = gc
+}
+
+func (c *Collectors) Remove(gc *GarbageCollector) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if c.gcs[gc.api.Address()] == gc {
+		delete(c.gcs, gc.api.Address())
+	}
+}
+
+// RunAll starts a garbage collection run on all instances.
+func (c *Collectors) RunAll() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	for _, gc := range c.gcs {
+		gc.RunNow()
+	}
+}
+
+func (c *Collectors) statuses() []Status {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	res := make([]Status, 0, len(c.gcs))
+	for _, gc := range c.gcs {
+		res = append(res, gc.Status())
+	}
+	sort.Slice(res, func(a, b int) bool {
+		return res[a].Instance < res[b].Instance
+	})
+	return res
+}
+
+func (c *Collectors) get(r *http.Request) (*GarbageCollector, error) {
+	inst := r.URL.Query().Get("instance")
+	if inst == "" {
+		return nil, admin.BadRequest("missing instance")
+	}
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	gc, ok := c.gcs[inst]
+	if !ok {
+		return nil, admin.NotFound("no garbage collector for instance %s", inst)
+	}
+	return gc, nil
+}
+
+// RegisterAdmin adds the garbage collection endpoints to the admin API.
+func (c *Collectors) RegisterAdmin(srv *admin.Server) {
+	srv.Handle(http.MethodGet, "/rest/gc", func(_ *http.Request) (any, error) {
+		return c.statuses(), nil
+	})
+	srv.Handle(http.MethodPost, "/rest/gc/run", func(r *http.Request) (any, error) {
+		if r.URL.Query().Get("instance") == "" {
+			c.RunAll()
+			return c.statuses(), nil
+		}
+		gc, err := c.get(r)
+		if err != nil {
+			return nil, err
+		}
+		gc.RunNow()
+		return gc.Status(), nil
+	})
+	srv.Handle(http.MethodGet, "/rest/gc/plan", func(r *http.Request) (any, error) {
+		gc, err := c.get(r)
+		if err != nil {
+			return nil, err
+		}
+		return gc.Plan()
+	})
+	srv.Handle(http.MethodPost, "/rest/gc/apply", func(r *http.Request) (any, error) {
+		gc, err := c.get(r)
+		if err != nil {
+			return nil, err
+		}
+		res, err := gc.ApplyNow(r.Context(), r.URL.Query().Get("override") == "true")
+		if errors.Is(err, errLimitExceeded) {
+			return nil, admin.Conflict("%v (override required)", err)
+		}
+		return res, err
+	})
+	srv.Handle(http.MethodPost, "/rest/gc/override", func(r *http.Request) (any, error) {
+		gc, err := c.get(r)
+		if err != nil {
+			return nil, err
+		}
+		gc.Override()
+		return gc.Status(), nil
+	})
+}
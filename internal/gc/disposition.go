@@ -0,0 +1,249 @@
+package gc
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+// disposeFolderData handles the data directory of a removed folder
+// according to its data disposition. Only folders created by configd, with
+// a path inside the creating pattern's path_root, are touched.
+func (s *GarbageCollector) disposeFolderData(myID protocol.DeviceID, id, path string, managedFld state.Folder) {
+	l := s.log.With("folder", id, "path", path)
+
+	pol := folderPolicy(s.cfg, managedFld, true)
+	disp, err := config.ParseDataDisposition(pol.dataDisposition)
+	if err != nil {
+		l.Error("Invalid data disposition", "error", err)
+		return
+	}
+	if disp.Action == config.DataKeep {
+		return
+	}
+
+	root := s.cfg.PatternByName(managedFld.Pattern).GetPathRoot()
+	if root == "" || !config.PathInside(path, root) {
+		l.Warn("Keeping folder data, as the path is not inside the pattern's path_root", "pathRoot", root)
+		return
+	}
+
+	switch disp.Action {
+	case config.DataDelete:
+		now := time.Now()
+		q := state.Quarantined{Folder: id, Removed: now, Due: now.Add(s.cfg.GarbageCollect.Quarantine())}
+		if err := s.state.Quarantine(myID, path, q); err != nil {
+			l.Error("Failed to quarantine folder data", "error", err)
+			return
+		}
+		l.Info("Folder data quarantined, awaiting deletion", "due", q.Due)
+
+	case config.DataMove:
+		dst := filepath.Join(disp.Dir, archiveName(id))
+		l.Info("Moving folder data", "destination", dst)
+		if err := os.MkdirAll(disp.Dir, 0o700); err != nil {
+			l.Error("Failed to move folder data", "error", err)
+			return
+		}
+		if err := moveDirectory(path, dst); err != nil {
+			l.Error("Failed to move folder data", "error", err)
+		}
+
+	case config.DataTar:
+		dst := filepath.Join(disp.Dir, archiveName(id)+".tar.gz")
+		l.Info("Archiving folder data", "destination", dst)
+		if err := archiveDirectory(path, dst); err != nil {
+			l.Error("Failed to archive folder data", "error", err)
+			return
+		}
+		if err := os.RemoveAll(path); err != nil {
+			l.Error("Failed to delete archived folder data", "error", err)
+		}
+	}
+}
+
+// processQuarantine deletes the quarantined folder data that is due,
+// unless the path has since been taken into use by a folder again.
+func (s *GarbageCollector) processQuarantine(myID protocol.DeviceID, cfg *stconfig.Configuration) {
+	inUse := make(map[string]bool)
+	for _, fld := range cfg.Folders {
+		inUse[filepath.Clean(fld.Path)] = true
+	}
+
+	now := time.Now()
+	for path, q := range s.state.Instance(myID).Quarantine {
+		l := s.log.With("folder", q.Folder, "path", path)
+		switch {
+		case inUse[filepath.Clean(path)]:
+			l.Info("Quarantined folder data is in use again, not deleting")
+		case now.Before(q.Due):
+			continue
+		default:
+			l.Info("Deleting quarantined folder data", "removed", q.Removed)
+			if err := os.RemoveAll(path); err != nil {
+				l.Error("Failed to delete folder data", "error", err)
+				continue
+			}
+		}
+		if err := s.state.Unquarantine(myID, path); err != nil {
+			l.Error("Failed to update quarantine", "error", err)
+		}
+	}
+}
+
+// archiveName returns a file name for an archive of the given folder.
+func archiveName(id string) string {
+	id = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' {
+			return '_'
+		}
+		return r
+	}, id)
+	return fmt.Sprintf("%s-%s", id, time.Now().UTC().Format("20060102-150405"))
+}
+
+// moveDirectory moves the directory src to dst, copying it if they are on
+// different file systems. The source is only removed once the copy is
+// complete.
+func moveDirectory(src, dst string) error {
+	err := os.Rename(src, dst)
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+	if err := copyDirectory(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyDirectory copies the directory src, with its files, directories and
+// symlinks, to dst, which must not exist.
+func copyDirectory(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			return os.Mkdir(target, info.Mode().Perm())
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		return nil // sockets, devices and the like are skipped
+	})
+}
+
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// archiveDirectory writes a gzipped tar archive of the directory to dst.
+func archiveDirectory(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o700); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	gw := gzip.NewWriter(out)
+	tw := tar.NewWriter(gw)
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var link string
+		if info.Mode()&fs.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if d.IsDir() {
+			hdr.Name += "/"
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		fd, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer fd.Close()
+		_, err = io.Copy(tw, fd)
+		return err
+	})
+	if err != nil {
+		os.Remove(dst)
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	return out.Close()
+}
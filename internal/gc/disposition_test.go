@@ -0,0 +1,210 @@
+package gc
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"kastelo.dev/syncthing-configd/internal/api/apitest"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+func TestDisposeFolderData(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	root := filepath.Join(tmp, "device-folders")
+	archive := filepath.Join(tmp, "archive")
+
+	makeFolder := func(name string) string {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Join(path, "sub"), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(path, "sub", "file"), []byte("data"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	st, err := state.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &GarbageCollector{
+		log:   slog.Default(),
+		state: st,
+		cfg: &config.Configuration{
+			GarbageCollect: &config.GarbageCollection{
+				DataDisposition: "delete", // with the default quarantine
+			},
+			Pattern: []*config.DevicePattern{
+				{Name: "rooted", PathRoot: root},
+				{Name: "unrooted"},
+				{
+					Name:           "archived",
+					PathRoot:       root,
+					GarbageCollect: &config.GarbageCollectionPolicy{DataDisposition: ptr("tar_to:" + archive)},
+				},
+			},
+		},
+	}
+
+	// Deletion goes via quarantine
+	deleted := makeFolder("deleted")
+	s.disposeFolderData(myID, "deleted", deleted, state.Folder{Pattern: "rooted"})
+	if !exists(deleted) {
+		t.Fatal("quarantined data was deleted immediately")
+	}
+	q, ok := st.Instance(myID).Quarantine[deleted]
+	if !ok {
+		t.Fatal("data not quarantined")
+	}
+	if want := time.Now().Add(7 * 24 * time.Hour); q.Due.Before(want.Add(-time.Minute)) || q.Due.After(want) {
+		t.Errorf("unexpected due time %v", q.Due)
+	}
+
+	// Quarantine is processed once due, unless the path is in use again
+	reused := makeFolder("reused")
+	_ = st.Quarantine(myID, reused, state.Quarantined{Due: time.Now().Add(-time.Hour)})
+	_ = st.Quarantine(myID, deleted, state.Quarantined{Due: time.Now().Add(-time.Hour)})
+	s.processQuarantine(myID, &stconfig.Configuration{
+		Folders: []stconfig.FolderConfiguration{{ID: "reused", Path: reused}},
+	})
+	if exists(deleted) {
+		t.Error("quarantined data was not deleted when due")
+	}
+	if !exists(reused) {
+		t.Error("quarantined data in use was deleted")
+	}
+	if q := st.Instance(myID).Quarantine; len(q) != 0 {
+		t.Errorf("quarantine not emptied: %v", q)
+	}
+
+	// Data outside of the path root, or without one, is left alone
+	unrooted := makeFolder("unrooted")
+	s.disposeFolderData(myID, "unrooted", unrooted, state.Folder{Pattern: "unrooted"})
+	if !exists(unrooted) {
+		t.Error("data without path root was touched")
+	}
+
+	// Archiving
+	archived := makeFolder("archived")
+	s.disposeFolderData(myID, "archived", archived, state.Folder{Pattern: "archived"})
+	if exists(archived) {
+		t.Error("archived data was not removed")
+	}
+	matches, _ := filepath.Glob(filepath.Join(archive, "archived-*.tar.gz"))
+	if len(matches) != 1 {
+		t.Errorf("expected one archive, got %v", matches)
+	}
+}
+
+func TestRunQuarantine(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), "device-folders")
+	path := filepath.Join(root, "orphan")
+	if err := os.MkdirAll(path, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	exists := func() bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	syncthing, api := apitest.New(t, myID, stconfig.Configuration{
+		Devices: []stconfig.DeviceConfiguration{{DeviceID: myID}},
+		Folders: []stconfig.FolderConfiguration{
+			{ID: "orphan", Path: path, Devices: []stconfig.FolderDeviceConfiguration{{DeviceID: myID}}},
+		},
+	})
+	st, err := state.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := st.AddFolder(myID, "orphan", state.Folder{Pattern: "rooted", Device: seenID, Path: path}); err != nil {
+		t.Fatal(err)
+	}
+	s := NewGarbageCollector(slog.Default(), api, &config.Configuration{
+		GarbageCollect: &config.GarbageCollection{
+			UnsharedFolders: true,
+			DataDisposition: "delete",
+			QuarantineDays:  7,
+		},
+		Pattern: []*config.DevicePattern{{Name: "rooted", PathRoot: root}},
+	}, nil, st)
+
+	// The folder removed in this run stays in quarantine
+	s.run(context.Background())
+	if status := s.Status(); status.LastError != "" || status.Report == nil || status.Report.Folders != 1 {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if n := len(syncthing.Config().Folders); n != 0 {
+		t.Fatalf("folder not removed, %d folders remain", n)
+	}
+	if _, ok := st.Instance(myID).Quarantine[path]; !ok {
+		t.Fatal("data of removed folder not in quarantine")
+	}
+	if !exists() {
+		t.Fatal("quarantined data was deleted immediately")
+	}
+
+	// and is deleted by the first run after it's due
+	_ = st.Quarantine(myID, path, state.Quarantined{Folder: "orphan", Due: time.Now().Add(-time.Hour)})
+	s.run(context.Background())
+	if exists() {
+		t.Error("quarantined data was not deleted when due")
+	}
+	if q := st.Instance(myID).Quarantine; len(q) != 0 {
+		t.Errorf("quarantine not emptied: %v", q)
+	}
+}
+
+func TestCopyDirectory(t *testing.T) {
+	t.Parallel()
+
+	tmp := t.TempDir()
+	src := filepath.Join(tmp, "src")
+	if err := os.MkdirAll(filepath.Join(src, "sub"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(src, "sub", "file"), []byte("data"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("sub/file", filepath.Join(src, "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := filepath.Join(tmp, "dst")
+	if err := copyDirectory(src, dst); err != nil {
+		t.Fatal(err)
+	}
+	if bs, err := os.ReadFile(filepath.Join(dst, "sub", "file")); err != nil || string(bs) != "data" {
+		t.Errorf("file not copied: %q, %v", bs, err)
+	}
+	if info, err := os.Stat(filepath.Join(dst, "sub", "file")); err != nil || info.Mode().Perm() != 0o640 {
+		t.Errorf("file mode not kept: %v, %v", info.Mode(), err)
+	}
+	if link, err := os.Readlink(filepath.Join(dst, "link")); err != nil || link != "sub/file" {
+		t.Errorf("symlink not copied: %q, %v", link, err)
+	}
+
+	// An existing destination is not overwritten
+	if err := copyDirectory(src, dst); err == nil {
+		t.Error("expected error copying onto existing directory")
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
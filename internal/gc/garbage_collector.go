@@ -2,112 +2,513 @@ package gc
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"math/rand"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
 	"kastelo.dev/syncthing-configd/internal/api"
 	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/cron"
+	"kastelo.dev/syncthing-configd/internal/hooks"
+	"kastelo.dev/syncthing-configd/internal/state"
 )
 
 type GarbageCollector struct {
-	log *slog.Logger
-	api *api.API
-	cfg *config.GarbageCollection
+	log      *slog.Logger
+	api      *api.API
+	cfg      *config.Configuration
+	hooks    *hooks.Runner
+	state    *state.Store
+	runNow   chan struct{}
+	override atomic.Bool
+	runMut   sync.Mutex // serialises scheduled runs and ApplyNow
+
+	mut    sync.Mutex
+	status Status
+	next   time.Time
 }
 
-func NewGarbageCollector(log *slog.Logger, api *api.API, cfg *config.GarbageCollection) *GarbageCollector {
-	return &GarbageCollector{
-		log: log.With("address", api.Address()),
-		api: api,
-		cfg: cfg,
-	}
+// Status is the outcome of the latest garbage collection run.
+type Status struct {
+	Instance        string    `json:"instance"`
+	LastRun         time.Time `json:"lastRun,omitempty"`
+	LastError       string    `json:"lastError,omitempty"`
+	Blocked         *Plan     `json:"blocked,omitempty"` // plan that exceeded the safety limits
+	OverridePending bool      `json:"overridePending"`
+	NextRun         time.Time `json:"nextRun,omitempty"`
+	Report          *Report   `json:"report,omitempty"` // of the last completed run
 }
 
-func (s *GarbageCollector) Serve(ctx context.Context) error {
-	interval := time.Duration(s.cfg.RunEveryS) * time.Second
-	next := time.Now().Truncate(interval).Add(interval)
+// Report summarises a completed garbage collection run.
+type Report struct {
+	Duration  time.Duration `json:"duration"`
+	Devices   int           `json:"devices"` // removed
+	Folders   int           `json:"folders"` // removed
+	Shares    int           `json:"shares"`  // removed
+	Protected int           `json:"protected"`
+	Skipped   int           `json:"skipped"` // no longer candidates when removed
+	Failed    int           `json:"failed"`
+}
 
-	stat, err := s.api.GetSystemStatus()
-	if err != nil {
-		s.log.Error("Failed to get Syncthing status", "error", err)
-		return err
+func NewGarbageCollector(log *slog.Logger, api *api.API, cfg *config.Configuration, hooks *hooks.Runner, state *state.Store) *GarbageCollector {
+	return &GarbageCollector{
+		log:    log.With("address", api.Address()),
+		api:    api,
+		cfg:    cfg,
+		hooks:  hooks,
+		state:  state,
+		runNow: make(chan struct{}, 1),
 	}
+}
 
+// Serve runs garbage collection on schedule and on request. Without a
+// schedule, it only runs on request.
+func (s *GarbageCollector) Serve(ctx context.Context) error {
 	for {
-		s.log.Debug("Waiting for next run", "next", next)
+		var scheduled <-chan time.Time
+		if s.cfg.GarbageCollect.Enabled() {
+			next, err := nextRun(s.cfg.GarbageCollect, time.Now())
+			if err != nil {
+				s.log.Error("Failed to schedule garbage collection", "error", err)
+				return err
+			}
+			s.setNext(next)
+			s.log.Debug("Waiting for next run", "next", next)
+			scheduled = time.After(time.Until(next))
+		}
 
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-time.After(time.Until(next)):
-			next = next.Add(interval)
+		case <-scheduled:
+		case <-s.runNow:
+			s.log.Info("Running garbage collection on request")
 		}
 
-		cfg, err := s.api.GetConfig()
+		s.run(ctx)
+	}
+}
+
+// nextRun returns the time of the next scheduled run after now, including
+// any jitter. A cron schedule takes precedence over run_every_s.
+func nextRun(gc *config.GarbageCollection, now time.Time) (time.Time, error) {
+	var next time.Time
+	if gc.GetSchedule() != "" {
+		sched, err := cron.Parse(gc.Schedule)
 		if err != nil {
-			s.log.Error("Failed to get Syncthing configuration", "error", err)
-			return err
+			return time.Time{}, err
 		}
-
-		if s.cfg.UnseenDevicesDays > 0 {
-			s.gcUnseenDevices(cfg, stat)
+		loc, err := gc.Location()
+		if err != nil {
+			return time.Time{}, err
 		}
-
-		if s.cfg.UnsharedFolders {
-			s.gcUnsharedFolders(cfg)
+		next = sched.Next(now.In(loc))
+		if next.IsZero() {
+			return time.Time{}, fmt.Errorf("schedule %q never matches", gc.Schedule)
 		}
+	} else {
+		interval := time.Duration(gc.GetRunEveryS()) * time.Second
+		next = now.Truncate(interval).Add(interval)
+	}
+	if jitter := time.Duration(gc.GetJitterS()) * time.Second; jitter > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(jitter))))
 	}
+	return next, nil
 }
 
 func (s *GarbageCollector) String() string {
 	return fmt.Sprintf("garbageCollector(%s)@%p", s.api.Address(), s)
 }
 
-func (s *GarbageCollector) gcUnseenDevices(cfg *stconfig.Configuration, stat *api.SystemStatus) {
-	// Gather device statics; remove devices with a last seen time older
-	// than the threshold.
+// Override allows the next run to exceed the safety limits, and starts it
+// immediately.
+func (s *GarbageCollector) Override() {
+	s.override.Store(true)
+	s.RunNow()
+}
+
+// RunNow starts a garbage collection run, unless one is already pending.
+func (s *GarbageCollector) RunNow() {
+	select {
+	case s.runNow <- struct{}{}:
+	default:
+	}
+}
 
-	stats, err := s.api.GetDeviceStats()
+// Status returns the outcome of the latest run.
+func (s *GarbageCollector) Status() Status {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	st := s.status
+	st.Instance = s.api.Address()
+	st.OverridePending = s.override.Load()
+	st.NextRun = s.next
+	return st
+}
+
+func (s *GarbageCollector) run(ctx context.Context) {
+	s.runMut.Lock()
+	defer s.runMut.Unlock()
+	t0 := time.Now()
+	plan, err := s.plan(true)
 	if err != nil {
-		s.log.Error("Failed to get Syncthing device stats", "error", err)
+		s.log.Error("Aborting garbage collection run", "error", err)
+		s.setStatus(Status{LastError: err.Error()})
+		return
 	}
 
-	for _, dev := range cfg.Devices {
-		if dev.DeviceID == stat.MyID {
-			continue
+	if err := plan.CheckLimits(s.cfg.GarbageCollect); err != nil {
+		if !s.override.Swap(false) {
+			devices, folders, shares := plan.Removals()
+			s.log.Error("Aborting garbage collection run, safety limit exceeded; an admin override is required to proceed", "error", err, "devices", devices, "folders", folders, "shares", shares)
+			s.setStatus(Status{LastError: err.Error(), Blocked: plan})
+			s.hooks.Go(ctx, &hooks.Payload{
+				Event:    hooks.EventGCAlert,
+				Instance: s.api.Address(),
+				Message:  err.Error(),
+			})
+			return
 		}
+		s.log.Warn("Safety limit exceeded, proceeding due to admin override", "error", err)
+	}
+	s.override.Store(false)
+
+	rep := s.apply(ctx, plan)
+	// The configuration from planning still has the folders just removed,
+	// whose data would then be taken to be in use again.
+	if cfg, err := s.api.GetConfig(); err != nil {
+		s.log.Error("Failed to get configuration, not processing quarantine", "error", err)
+	} else {
+		s.processQuarantine(plan.MyID, cfg)
+	}
+	rep.Duration = time.Since(t0)
+	s.log.Info("Garbage collection run complete", "devices", rep.Devices, "folders", rep.Folders, "shares", rep.Shares, "protected", rep.Protected, "skipped", rep.Skipped, "failed", rep.Failed, "duration", rep.Duration)
+	s.setStatus(Status{Report: rep})
+}
 
-		lastSeen := stats[dev.DeviceID].LastSeen
-		daysSince := int(time.Since(lastSeen) / time.Hour / 24)
+// Plan returns what a run would remove right now, without changing
+// anything. Share activity is not checked; the activity last recorded in
+// the state is used instead.
+func (s *GarbageCollector) Plan() (*Plan, error) {
+	return s.plan(false)
+}
+
+// Applied is the outcome of ApplyNow.
+type Applied struct {
+	Plan   *Plan   `json:"plan"`
+	Report *Report `json:"report"`
+}
 
-		s.log.Debug("Checking device", "device", dev.DeviceID, "name", dev.Name, "lastSeen", lastSeen, "daysSince", daysSince)
+var errLimitExceeded = errors.New("safety limit exceeded")
 
-		if lastSeen.IsZero() || lastSeen.Unix() == 0 {
-			// Not seen at all; skip for now.
-			s.log.Debug("Skipping device", "device", dev.DeviceID, "name", dev.Name, "reason", "never seen")
-			continue
+// ApplyNow works out the plan, like Plan, and performs the removals in it
+// unless it exceeds the safety limits and override isn't set. It waits for
+// any resulting hooks to complete.
+func (s *GarbageCollector) ApplyNow(ctx context.Context, override bool) (*Applied, error) {
+	s.runMut.Lock()
+	defer s.runMut.Unlock()
+	t0 := time.Now()
+	plan, err := s.plan(false)
+	if err != nil {
+		return nil, err
+	}
+	if err := plan.CheckLimits(s.cfg.GarbageCollect); err != nil && !override {
+		return nil, fmt.Errorf("%w: %v", errLimitExceeded, err)
+	}
+	rep := s.apply(ctx, plan)
+	rep.Duration = time.Since(t0)
+	s.hooks.Wait()
+	s.log.Info("Applied garbage collection plan on request", "devices", rep.Devices, "folders", rep.Folders, "shares", rep.Shares, "protected", rep.Protected, "skipped", rep.Skipped, "failed", rep.Failed)
+	return &Applied{Plan: plan, Report: rep}, nil
+}
+
+// plan gets the current configuration and statistics from Syncthing and
+// works out the removals, within a configuration transaction so that no
+// other changes are made meanwhile. The instance's identity is checked
+// against the one seen by the event listener, to avoid acting on the wrong
+// instance if it has been replaced. With updateShares, share activity is
+// checked and recorded first, outside of the transaction as it takes a
+// request per share.
+func (s *GarbageCollector) plan(updateShares bool) (*Plan, error) {
+	if updateShares && staleSharesEnabled(s.cfg) {
+		stat, err := s.api.GetSystemStatus()
+		if err != nil {
+			return nil, fmt.Errorf("getting status: %w", err)
+		}
+		if err := s.api.VerifyIdentity(stat.MyID); err != nil {
+			return nil, err
+		}
+		if err := s.updateShares(stat.MyID); err != nil {
+			return nil, fmt.Errorf("updating share activity: %w", err)
 		}
+	}
 
-		if daysSince > int(s.cfg.UnseenDevicesDays) {
-			s.log.Info("Removing device", "device", dev.DeviceID, "name", dev.Name)
-			if err := s.api.RemoveDevice(dev.DeviceID); err != nil {
-				s.log.Error("Failed to remove device", "device", dev.DeviceID, "name", dev.Name, "error", err)
-			}
+	var plan *Plan
+	err := s.api.InConfigTx(func(tx *api.ConfigTx) error {
+		var err error
+		plan, err = s.planTx(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func (s *GarbageCollector) planTx(tx *api.ConfigTx) (*Plan, error) {
+	stat, err := tx.GetSystemStatus()
+	if err != nil {
+		return nil, fmt.Errorf("getting status: %w", err)
+	}
+	if err := s.api.VerifyIdentity(stat.MyID); err != nil {
+		return nil, err
+	}
+	stats, err := tx.GetDeviceStats()
+	if err != nil {
+		return nil, fmt.Errorf("getting device stats: %w", err)
+	}
+	managed := s.state.Instance(stat.MyID)
+
+	return makePlan(planInput{
+		now:     time.Now(),
+		myID:    stat.MyID,
+		cfg:     tx.Config,
+		stats:   stats,
+		managed: managed,
+		conf:    s.cfg,
+		shares:  managed.Shares,
+	}), nil
+}
+
+var errNoLongerCandidate = errors.New("no longer a candidate for removal")
+
+// removeChecked calls remove within a configuration transaction, after
+// checking that a plan made within the same transaction still has the
+// candidate. Changes made since the plan was made, such as a device
+// accepted again or a folder shared anew, are then left alone.
+func (s *GarbageCollector) removeChecked(myID protocol.DeviceID, cand Candidate, remove func(tx *api.ConfigTx) error) error {
+	return s.api.InConfigTx(func(tx *api.ConfigTx) error {
+		plan, err := s.planTx(tx)
+		if err != nil {
+			return err
+		}
+		if plan.MyID != myID {
+			return fmt.Errorf("%w from %s to %s", api.ErrIdentityMismatch, myID.Short(), plan.MyID.Short())
 		}
+		if !plan.has(cand) {
+			return errNoLongerCandidate
+		}
+		return remove(tx)
+	})
+}
+
+func (s *GarbageCollector) apply(ctx context.Context, plan *Plan) *Report {
+	myID := plan.MyID
+	rep := &Report{}
+	count := func(err error, n *int) {
+		switch {
+		case err == nil:
+			*n++
+		case errors.Is(err, errNoLongerCandidate):
+			rep.Skipped++
+		default:
+			rep.Failed++
+		}
+	}
+
+	for _, dev := range plan.Devices {
+		if dev.Protected {
+			s.log.Debug("Skipping protected device", "device", dev.ID, "name", dev.Name, "reason", dev.Reason)
+			rep.Protected++
+			continue
+		}
+		s.log.Info("Removing device", "device", dev.ID, "name", dev.Name, "lastSeen", dev.LastSeen, "reason", dev.Reason)
+		id, err := protocol.DeviceIDFromString(dev.ID)
+		if err != nil {
+			s.log.Error("Failed to parse device ID", "device", dev.ID, "error", err)
+			rep.Failed++
+			continue
+		}
+		count(s.removeDevice(ctx, myID, id, dev), &rep.Devices)
+	}
+	for _, fld := range plan.Folders {
+		if fld.Protected {
+			s.log.Debug("Skipping protected folder", "folder", fld.ID, "label", fld.Name, "reason", fld.Reason)
+			rep.Protected++
+			continue
+		}
+		s.log.Info("Removing folder", "folder", fld.ID, "label", fld.Name, "reason", fld.Reason)
+		managedFld, managed := s.state.Folder(myID, fld.ID)
+		err := s.removeFolder(ctx, myID, fld)
+		if err == nil && managed {
+			s.disposeFolderData(myID, fld.ID, fld.Path, managedFld)
+		}
+		count(err, &rep.Folders)
 	}
+	for _, share := range plan.Shares {
+		if share.Protected {
+			s.log.Debug("Skipping protected share", "folder", share.ID, "device", share.Device, "reason", share.Reason)
+			rep.Protected++
+			continue
+		}
+		s.log.Info("Unsharing folder", "folder", share.ID, "label", share.Name, "device", share.Device, "lastActive", share.LastSeen, "reason", share.Reason)
+		id, err := protocol.DeviceIDFromString(share.Device)
+		if err != nil {
+			s.log.Error("Failed to parse device ID", "device", share.Device, "error", err)
+			rep.Failed++
+			continue
+		}
+		count(s.unshareFolder(ctx, myID, id, share), &rep.Shares)
+	}
+	return rep
 }
 
-func (s *GarbageCollector) gcUnsharedFolders(cfg *stconfig.Configuration) {
-	// Remove folders that are not shared with anyone.
+// updateShares records when each folder share was last active. Shares
+// seen for the first time are considered active, so that they are only
+// removed after being inactive for the full period.
+func (s *GarbageCollector) updateShares(myID protocol.DeviceID) error {
+	cfg, err := s.api.GetConfig()
+	if err != nil {
+		return fmt.Errorf("getting config: %w", err)
+	}
+	folderStats, err := s.api.GetFolderStats()
+	if err != nil {
+		return fmt.Errorf("getting folder stats: %w", err)
+	}
+	prev := s.state.Instance(myID).Shares
+	now := time.Now()
+	shares := make(map[string]map[protocol.DeviceID]time.Time)
 	for _, fld := range cfg.Folders {
-		s.log.Debug("Checking folder", "folder", fld.ID, "label", fld.Label, "numDevices", len(fld.Devices))
-		if len(fld.Devices) == 1 { // only self
-			s.log.Info("Removing folder", "folder", fld.ID, "label", fld.Label)
-			if err := s.api.RemoveFolder(fld.ID); err != nil {
-				s.log.Error("Failed to remove folder", "folder", fld.ID, "label", fld.Label, "error", err)
+		remotes := 0
+		for _, dev := range fld.Devices {
+			if dev.DeviceID != myID {
+				remotes++
+			}
+		}
+		for _, dev := range fld.Devices {
+			if dev.DeviceID == myID {
+				continue
+			}
+			comp, err := s.api.GetCompletion(fld.ID, dev.DeviceID)
+			if err != nil {
+				return fmt.Errorf("folder %s, device %s: %w", fld.ID, dev.DeviceID.Short(), err)
 			}
+			lastActive, ok := prev[fld.ID][dev.DeviceID]
+			if !ok {
+				lastActive = now
+			}
+			if shares[fld.ID] == nil {
+				shares[fld.ID] = make(map[protocol.DeviceID]time.Time)
+			}
+			shares[fld.ID][dev.DeviceID] = shareActivity(lastActive, comp, folderStats[fld.ID], remotes == 1, now)
+		}
+	}
+	return s.state.SetShares(myID, shares)
+}
+
+// shareActivity returns when a folder share was last active, given when it
+// was previously known to be. A share is active while the device is
+// connected and has the folder shared with us in turn, according to the
+// completion. When the device is the only one the folder is shared with,
+// the last file received in the folder also came from it, which catches
+// devices that were only connected in between runs.
+func shareActivity(prev time.Time, comp *api.FolderCompletion, stats api.FolderStatistics, onlyRemote bool, now time.Time) time.Time {
+	if comp.RemoteState == api.RemoteStateValid {
+		return now
+	}
+	if at := stats.LastFile.At; onlyRemote && at.After(prev) {
+		if at.After(now) {
+			return now
 		}
+		return at
+	}
+	return prev
+}
+
+func (s *GarbageCollector) setNext(t time.Time) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.next = t
+}
+
+func (s *GarbageCollector) setStatus(st Status) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	st.LastRun = time.Now()
+	s.status = st
+}
+
+func (s *GarbageCollector) removeDevice(ctx context.Context, myID, id protocol.DeviceID, cand Candidate) error {
+	err := s.removeChecked(myID, cand, func(tx *api.ConfigTx) error {
+		return tx.RemoveDevice(id)
+	})
+	if errors.Is(err, errNoLongerCandidate) {
+		s.log.Info("Not removing device, as it's no longer a candidate", "device", id, "name", cand.Name)
+		return err
+	}
+	if err != nil {
+		s.log.Error("Failed to remove device", "device", id, "name", cand.Name, "error", err)
+		return err
+	}
+	if err := s.state.RemoveDevice(myID, id); err != nil {
+		s.log.Error("Failed to forget managed device", "device", id, "error", err)
+	}
+	s.hooks.Go(ctx, &hooks.Payload{
+		Event:    hooks.EventGCRemove,
+		Instance: s.api.Address(),
+		Device:   id.String(),
+		Name:     cand.Name,
+	})
+	return nil
+}
+
+func (s *GarbageCollector) removeFolder(ctx context.Context, myID protocol.DeviceID, cand Candidate) error {
+	err := s.removeChecked(myID, cand, func(tx *api.ConfigTx) error {
+		return tx.RemoveFolder(cand.ID)
+	})
+	if errors.Is(err, errNoLongerCandidate) {
+		s.log.Info("Not removing folder, as it's no longer a candidate", "folder", cand.ID)
+		return err
+	}
+	if err != nil {
+		s.log.Error("Failed to remove folder", "folder", cand.ID, "error", err)
+		return err
+	}
+	if err := s.state.RemoveFolder(myID, cand.ID); err != nil {
+		s.log.Error("Failed to forget managed folder", "folder", cand.ID, "error", err)
+	}
+	s.hooks.Go(ctx, &hooks.Payload{
+		Event:    hooks.EventGCRemove,
+		Instance: s.api.Address(),
+		Folders:  []string{cand.ID},
+		Path:     cand.Path,
+	})
+	return nil
+}
+
+func (s *GarbageCollector) unshareFolder(ctx context.Context, myID, deviceID protocol.DeviceID, cand Candidate) error {
+	err := s.removeChecked(myID, cand, func(tx *api.ConfigTx) error {
+		return tx.RemoveFolderDevice(cand.ID, deviceID)
+	})
+	if errors.Is(err, errNoLongerCandidate) {
+		s.log.Info("Not unsharing folder, as the share is no longer a candidate", "folder", cand.ID, "device", deviceID)
+		return err
+	}
+	if err != nil {
+		s.log.Error("Failed to unshare folder", "folder", cand.ID, "device", deviceID, "error", err)
+		return err
 	}
+	s.hooks.Go(ctx, &hooks.Payload{
+		Event:    hooks.EventGCUnshare,
+		Instance: s.api.Address(),
+		Device:   deviceID.String(),
+		Folders:  []string{cand.ID},
+		Path:     cand.Path,
+	})
+	return nil
 }
@@ -0,0 +1,182 @@
+package gc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/api/apitest"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+func TestNextRun(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 8, 15, 12, 34, 56, 0, time.UTC)
+	cases := []struct {
+		gc   *config.GarbageCollection
+		want time.Time
+	}{
+		{&config.GarbageCollection{RunEveryS: 86400}, time.Date(2024, 8, 16, 0, 0, 0, 0, time.UTC)},
+		{&config.GarbageCollection{RunEveryS: 3600}, time.Date(2024, 8, 15, 13, 0, 0, 0, time.UTC)},
+		{&config.GarbageCollection{RunEveryS: 86400, Schedule: "30 3 * * *"}, time.Date(2024, 8, 16, 3, 30, 0, 0, time.UTC)},
+		{&config.GarbageCollection{Schedule: "30 3 * * *", Timezone: "America/New_York"}, time.Date(2024, 8, 16, 7, 30, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		got, err := nextRun(c.gc, now)
+		if err != nil {
+			t.Errorf("nextRun(%v) returned error: %v", c.gc, err)
+		} else if !got.Equal(c.want) {
+			t.Errorf("nextRun(%v) = %v, want %v", c.gc, got, c.want)
+		}
+	}
+
+	// Jitter delays the run by up to the given amount
+	gc := &config.GarbageCollection{Schedule: "@daily", JitterS: 600}
+	base := time.Date(2024, 8, 16, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 100; i++ {
+		got, err := nextRun(gc, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Before(base) || !got.Before(base.Add(10*time.Minute)) {
+			t.Fatalf("nextRun with jitter = %v, want within 10 minutes after %v", got, base)
+		}
+	}
+
+	if _, err := nextRun(&config.GarbageCollection{Schedule: "0 0 30 2 *"}, now); err == nil {
+		t.Error("nextRun with impossible schedule returned nil error")
+	}
+}
+
+func TestShareActivity(t *testing.T) {
+	t.Parallel()
+
+	prev := daysBefore(10)
+	received := func(at time.Time) api.FolderStatistics {
+		var stats api.FolderStatistics
+		stats.LastFile.At = at
+		return stats
+	}
+	valid := &api.FolderCompletion{RemoteState: api.RemoteStateValid}
+	paused := &api.FolderCompletion{RemoteState: "paused"}
+
+	cases := []struct {
+		name       string
+		comp       *api.FolderCompletion
+		stats      api.FolderStatistics
+		onlyRemote bool
+		want       time.Time
+	}{
+		{"sharing now", valid, api.FolderStatistics{}, false, testNow},
+		{"not sharing", paused, api.FolderStatistics{}, true, prev},
+		{"file received since", paused, received(daysBefore(2)), true, daysBefore(2)},
+		{"file received before", paused, received(daysBefore(20)), true, prev},
+		{"file from any of several devices", paused, received(daysBefore(2)), false, prev},
+		{"file received in the future", paused, received(testNow.Add(time.Hour)), true, testNow},
+	}
+	for _, c := range cases {
+		if got := shareActivity(prev, c.comp, c.stats, c.onlyRemote, testNow); !got.Equal(c.want) {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestApplyNow(t *testing.T) {
+	t.Parallel()
+
+	self := []stconfig.FolderDeviceConfiguration{{DeviceID: myID}}
+	syncthing, api := apitest.New(t, myID, stconfig.Configuration{
+		Devices: []stconfig.DeviceConfiguration{{DeviceID: myID}},
+		Folders: []stconfig.FolderConfiguration{
+			{ID: "orphan", Path: "/srv/orphan", Devices: self},
+			{ID: "kept", Path: "/srv/kept", Devices: self},
+		},
+	})
+	st, err := state.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewGarbageCollector(slog.Default(), api, &config.Configuration{
+		GarbageCollect: &config.GarbageCollection{
+			UnsharedFolders:    true,
+			MaxRemovalFraction: 0.5,
+			Protect:            []string{"kept"},
+		},
+	}, nil, st)
+
+	res, err := s.ApplyNow(context.Background(), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Plan.Folders) != 2 || res.Report.Folders != 1 || res.Report.Protected != 1 {
+		t.Errorf("unexpected result: plan %+v, report %+v", res.Plan, res.Report)
+	}
+	if fs := syncthing.Config().Folders; len(fs) != 1 || fs[0].ID != "kept" {
+		t.Errorf("unexpected folders left: %v", fs)
+	}
+
+	// Removing the last unprotected folder exceeds the limit
+	s.cfg.GarbageCollect.Protect = nil
+	if _, err := s.ApplyNow(context.Background(), false); !errors.Is(err, errLimitExceeded) {
+		t.Fatalf("got error %v, want errLimitExceeded", err)
+	}
+	if len(syncthing.Config().Folders) != 1 {
+		t.Fatal("folder removed despite exceeding the limit")
+	}
+	if _, err := s.ApplyNow(context.Background(), true); err != nil {
+		t.Fatal(err)
+	}
+	if len(syncthing.Config().Folders) != 0 {
+		t.Error("folder not removed with override")
+	}
+}
+
+func TestApplyRechecks(t *testing.T) {
+	t.Parallel()
+
+	other := protocol.DeviceID{42}
+	self := []stconfig.FolderDeviceConfiguration{{DeviceID: myID}}
+	syncthing, api := apitest.New(t, myID, stconfig.Configuration{
+		Devices: []stconfig.DeviceConfiguration{{DeviceID: myID}},
+		Folders: []stconfig.FolderConfiguration{
+			{ID: "orphan", Path: "/srv/orphan", Devices: self},
+			{ID: "shared", Path: "/srv/shared", Devices: self},
+		},
+	})
+	st, err := state.Open("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewGarbageCollector(slog.Default(), api, &config.Configuration{
+		GarbageCollect: &config.GarbageCollection{UnsharedFolders: true},
+	}, nil, st)
+
+	plan, err := s.plan(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Folders) != 2 {
+		t.Fatalf("unexpected plan %+v", plan)
+	}
+
+	// The folder is shared after planning, e.g. by the event listener
+	// accepting a device, and must survive
+	if _, err := api.SetFolder(&stconfig.FolderConfiguration{ID: "shared", Devices: []stconfig.FolderDeviceConfiguration{{DeviceID: other}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rep := s.apply(context.Background(), plan)
+	if rep.Folders != 1 || rep.Skipped != 1 || rep.Failed != 0 {
+		t.Errorf("unexpected report %+v", rep)
+	}
+	if fs := syncthing.Config().Folders; len(fs) != 1 || fs[0].ID != "shared" {
+		t.Errorf("unexpected folders left: %v", fs)
+	}
+}
@@ -0,0 +1,272 @@
+package gc
+
+import (
+	"fmt"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+const (
+	reasonUnseen    = "unseen"
+	reasonNeverSeen = "never seen"
+	reasonUnshared  = "unshared"
+	reasonDevice    = "device removed"
+	reasonStale     = "stale share"
+)
+
+// A Candidate is a device or folder considered for removal.
+type Candidate struct {
+	Type     string    `json:"type"`             // "device", "folder" or "share"
+	ID       string    `json:"id"`               // device or folder ID
+	Device   string    `json:"device,omitempty"` // the device a share is with
+	Name     string    `json:"name,omitempty"`   // device name or folder label
+	Path     string    `json:"path,omitempty"`
+	LastSeen time.Time `json:"lastSeen,omitempty"` // for shares, last active
+	Pattern  string    `json:"pattern,omitempty"`  // the pattern that created it, if managed
+	Reason   string    `json:"reason"`
+	// Protected candidates would have been removed, but are protected by
+	// the protect list or a pattern policy.
+	Protected bool `json:"protected,omitempty"`
+}
+
+// A Plan is the set of removals a garbage collection run would perform.
+type Plan struct {
+	MyID         protocol.DeviceID `json:"myID"` // the instance the plan is for
+	Devices      []Candidate       `json:"devices"`
+	Folders      []Candidate       `json:"folders"`
+	Shares       []Candidate       `json:"shares,omitempty"` // folders to unshare from devices
+	TotalDevices int               `json:"totalDevices"`     // excluding ourselves
+	TotalFolders int               `json:"totalFolders"`
+	TotalShares  int               `json:"totalShares"`
+}
+
+type planInput struct {
+	now     time.Time
+	myID    protocol.DeviceID
+	cfg     *stconfig.Configuration
+	stats   map[protocol.DeviceID]api.DeviceStatistics
+	managed state.Instance
+	conf    *config.Configuration
+	shares  map[string]map[protocol.DeviceID]time.Time // last active
+}
+
+// makePlan works out which devices and folders should be removed. It
+// doesn't talk to Syncthing or change anything.
+func makePlan(in planInput) *Plan {
+	gc := in.conf.GetGarbageCollect()
+	p := &Plan{
+		MyID:         in.myID,
+		TotalDevices: len(in.cfg.Devices),
+		TotalFolders: len(in.cfg.Folders),
+	}
+	removedDevices := make(map[protocol.DeviceID]bool)
+	removedFolders := make(map[string]bool)
+
+	for _, dev := range in.cfg.Devices {
+		if dev.DeviceID == in.myID {
+			p.TotalDevices--
+			continue
+		}
+		managedDev, managed := in.managed.Devices[dev.DeviceID]
+		if gc.GetOnlyManaged() && !managed {
+			continue
+		}
+		pol := devicePolicy(in.conf, managedDev.Pattern)
+
+		cand := Candidate{
+			Type:      "device",
+			ID:        dev.DeviceID.String(),
+			Name:      dev.Name,
+			LastSeen:  in.stats[dev.DeviceID].LastSeen,
+			Pattern:   managedDev.Pattern,
+			Protected: pol.never || in.conf.IsProtected(dev.DeviceID.String()),
+		}
+
+		if cand.LastSeen.IsZero() || cand.LastSeen.Unix() == 0 {
+			// Not seen at all. If we accepted it ourselves we know for how
+			// long it has been around, and can remove it after the grace
+			// period. Otherwise, skip it.
+			cand.LastSeen = time.Time{}
+			if !managed || pol.neverSeenGraceDays <= 0 {
+				continue
+			}
+			if daysBetween(managedDev.Accepted, in.now) > pol.neverSeenGraceDays {
+				cand.Reason = reasonNeverSeen
+				p.Devices = append(p.Devices, cand)
+				if cand.Protected {
+					continue
+				}
+				removedDevices[dev.DeviceID] = true
+				for _, fld := range deviceFolders(in, dev.DeviceID) {
+					p.Folders = append(p.Folders, folderCandidate(in, fld, reasonDevice))
+					removedFolders[fld.ID] = true
+				}
+			}
+			continue
+		}
+
+		if pol.unseenDevicesDays > 0 && daysBetween(cand.LastSeen, in.now) > pol.unseenDevicesDays {
+			cand.Reason = reasonUnseen
+			p.Devices = append(p.Devices, cand)
+			removedDevices[dev.DeviceID] = !cand.Protected
+		}
+	}
+
+	for _, fld := range in.cfg.Folders {
+		if len(fld.Devices) != 1 || removedFolders[fld.ID] { // only self
+			continue
+		}
+		managedFld, managed := in.managed.Folders[fld.ID]
+		if gc.GetOnlyManaged() && !managed {
+			continue
+		}
+		if !folderPolicy(in.conf, managedFld, managed).unsharedFolders {
+			continue
+		}
+		p.Folders = append(p.Folders, folderCandidate(in, fld, reasonUnshared))
+	}
+
+	for _, fld := range in.cfg.Folders {
+		managedFld, fldManaged := in.managed.Folders[fld.ID]
+		for _, dev := range fld.Devices {
+			if dev.DeviceID == in.myID {
+				continue
+			}
+			p.TotalShares++
+			if removedDevices[dev.DeviceID] {
+				continue
+			}
+			managedDev, devManaged := in.managed.Devices[dev.DeviceID]
+			if gc.GetOnlyManaged() && !fldManaged && !devManaged {
+				continue
+			}
+			pol := sharePolicy(in.conf, managedFld, fldManaged, managedDev)
+			lastActive, ok := in.shares[fld.ID][dev.DeviceID]
+			if !ok || pol.staleSharesDays <= 0 || daysBetween(lastActive, in.now) <= pol.staleSharesDays {
+				continue
+			}
+			pattern := managedFld.Pattern
+			if !fldManaged {
+				pattern = managedDev.Pattern
+			}
+			p.Shares = append(p.Shares, Candidate{
+				Type:      "share",
+				ID:        fld.ID,
+				Device:    dev.DeviceID.String(),
+				Name:      fld.Label,
+				Path:      fld.Path,
+				LastSeen:  lastActive,
+				Pattern:   pattern,
+				Reason:    reasonStale,
+				Protected: pol.never || in.conf.IsProtected(fld.ID) || in.conf.IsProtected(dev.DeviceID.String()),
+			})
+		}
+	}
+
+	return p
+}
+
+// Removals returns the number of devices, folders and shares the plan
+// removes, i.e. the candidates that are not protected.
+func (p *Plan) Removals() (devices, folders, shares int) {
+	for _, c := range p.Devices {
+		if !c.Protected {
+			devices++
+		}
+	}
+	for _, c := range p.Folders {
+		if !c.Protected {
+			folders++
+		}
+	}
+	for _, c := range p.Shares {
+		if !c.Protected {
+			shares++
+		}
+	}
+	return devices, folders, shares
+}
+
+// deviceFolders returns the managed folders that were created for the
+// given device and are not shared with any other device.
+func deviceFolders(in planInput, deviceID protocol.DeviceID) []stconfig.FolderConfiguration {
+	var res []stconfig.FolderConfiguration
+nextFolder:
+	for _, fld := range in.cfg.Folders {
+		managedFld, managed := in.managed.Folders[fld.ID]
+		if !managed || managedFld.Device != deviceID {
+			continue
+		}
+		for _, dev := range fld.Devices {
+			if dev.DeviceID != in.myID && dev.DeviceID != deviceID {
+				continue nextFolder
+			}
+		}
+		res = append(res, fld)
+	}
+	return res
+}
+
+func folderCandidate(in planInput, fld stconfig.FolderConfiguration, reason string) Candidate {
+	managedFld, managed := in.managed.Folders[fld.ID]
+	pol := folderPolicy(in.conf, managedFld, managed)
+	return Candidate{
+		Type:      "folder",
+		ID:        fld.ID,
+		Name:      fld.Label,
+		Path:      fld.Path,
+		Pattern:   managedFld.Pattern,
+		Reason:    reason,
+		Protected: pol.never || in.conf.IsProtected(fld.ID),
+	}
+}
+
+// CheckLimits returns an error if the plan exceeds the configured safety
+// limits.
+func (p *Plan) CheckLimits(gc *config.GarbageCollection) error {
+	devices, folders, shares := p.Removals()
+	if max := int(gc.GetMaxRemovalsPerRun()); max > 0 && devices+folders+shares > max {
+		return fmt.Errorf("%d removals exceeds max_removals_per_run %d", devices+folders+shares, max)
+	}
+	if max := gc.GetMaxRemovalFraction(); max > 0 {
+		if frac := fraction(devices, p.TotalDevices); frac > max {
+			return fmt.Errorf("removing %d of %d devices exceeds max_removal_fraction %g", devices, p.TotalDevices, max)
+		}
+		if frac := fraction(folders, p.TotalFolders); frac > max {
+			return fmt.Errorf("removing %d of %d folders exceeds max_removal_fraction %g", folders, p.TotalFolders, max)
+		}
+		if frac := fraction(shares, p.TotalShares); frac > max {
+			return fmt.Errorf("removing %d of %d shares exceeds max_removal_fraction %g", shares, p.TotalShares, max)
+		}
+	}
+	return nil
+}
+
+func fraction(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from) / time.Hour / 24)
+}
+
+// has returns true if the plan would remove the candidate: the same device,
+// folder or share is in the plan and isn't protected.
+func (p *Plan) has(cand Candidate) bool {
+	for _, cands := range [][]Candidate{p.Devices, p.Folders, p.Shares} {
+		for _, c := range cands {
+			if c.Type == cand.Type && c.ID == cand.ID && c.Device == cand.Device {
+				return !c.Protected
+			}
+		}
+	}
+	return false
+}
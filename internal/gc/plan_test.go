@@ -0,0 +1,246 @@
+package gc
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+var (
+	myID       = protocol.LocalDeviceID
+	seenID     = protocol.DeviceID{1}
+	unseenID   = protocol.DeviceID{2}
+	neverID    = protocol.DeviceID{3}
+	newID      = protocol.DeviceID{4}
+	manualID   = protocol.DeviceID{5}
+	testNow    = time.Date(2024, 8, 15, 0, 0, 0, 0, time.UTC)
+	daysBefore = func(days int) time.Time { return testNow.Add(-time.Duration(days) * 24 * time.Hour) }
+)
+
+func testPlanInput() planInput {
+	folder := func(id string, devs ...protocol.DeviceID) stconfig.FolderConfiguration {
+		fld := stconfig.FolderConfiguration{ID: id, Path: "/var/device-folders/" + id}
+		for _, dev := range append([]protocol.DeviceID{myID}, devs...) {
+			fld.Devices = append(fld.Devices, stconfig.FolderDeviceConfiguration{DeviceID: dev})
+		}
+		return fld
+	}
+
+	return planInput{
+		now:  testNow,
+		myID: myID,
+		cfg: &stconfig.Configuration{
+			Devices: []stconfig.DeviceConfiguration{
+				{DeviceID: myID},
+				{DeviceID: seenID, Name: "seen"},
+				{DeviceID: unseenID, Name: "unseen"},
+				{DeviceID: neverID, Name: "never"},
+				{DeviceID: newID, Name: "new"},
+				{DeviceID: manualID, Name: "manual"},
+			},
+			Folders: []stconfig.FolderConfiguration{
+				folder("default", seenID, unseenID, neverID, newID, manualID),
+				folder("never", neverID),
+				folder("orphan"),
+				folder("manual-orphan"),
+			},
+		},
+		stats: map[protocol.DeviceID]api.DeviceStatistics{
+			seenID:   {LastSeen: daysBefore(1)},
+			unseenID: {LastSeen: daysBefore(100)},
+			manualID: {LastSeen: daysBefore(100)},
+		},
+		managed: state.Instance{
+			Devices: map[protocol.DeviceID]state.Device{
+				seenID:   {Accepted: daysBefore(200)},
+				unseenID: {Accepted: daysBefore(200)},
+				neverID:  {Accepted: daysBefore(30)},
+				newID:    {Accepted: daysBefore(1)},
+			},
+			Folders: map[string]state.Folder{
+				"never":  {Device: neverID},
+				"orphan": {Device: unseenID},
+			},
+		},
+		conf: &config.Configuration{
+			GarbageCollect: &config.GarbageCollection{
+				UnseenDevicesDays:  90,
+				NeverSeenGraceDays: 14,
+				UnsharedFolders:    true,
+			},
+		},
+	}
+}
+
+func candidateIDs(cands []Candidate) []string {
+	var ids []string
+	for _, c := range cands {
+		ids = append(ids, c.ID)
+	}
+	return ids
+}
+
+func TestMakePlan(t *testing.T) {
+	t.Parallel()
+
+	in := testPlanInput()
+	p := makePlan(in)
+
+	wantDevices := []string{unseenID.String(), neverID.String(), manualID.String()}
+	if got := candidateIDs(p.Devices); !slices.Equal(got, wantDevices) {
+		t.Errorf("got devices %v, want %v", got, wantDevices)
+	}
+	wantFolders := []string{"never", "orphan", "manual-orphan"}
+	if got := candidateIDs(p.Folders); !slices.Equal(got, wantFolders) {
+		t.Errorf("got folders %v, want %v", got, wantFolders)
+	}
+	if p.TotalDevices != 5 || p.TotalFolders != 4 {
+		t.Errorf("got totals %d/%d, want 5/4", p.TotalDevices, p.TotalFolders)
+	}
+
+	// Only managed objects
+	in.conf.GarbageCollect.OnlyManaged = true
+	p = makePlan(in)
+	wantDevices = []string{unseenID.String(), neverID.String()}
+	if got := candidateIDs(p.Devices); !slices.Equal(got, wantDevices) {
+		t.Errorf("got managed devices %v, want %v", got, wantDevices)
+	}
+	wantFolders = []string{"never", "orphan"}
+	if got := candidateIDs(p.Folders); !slices.Equal(got, wantFolders) {
+		t.Errorf("got managed folders %v, want %v", got, wantFolders)
+	}
+}
+
+func TestPlanPolicies(t *testing.T) {
+	t.Parallel()
+
+	in := testPlanInput()
+	days := func(n int32) *int32 { return &n }
+	in.conf.Pattern = []*config.DevicePattern{
+		{
+			Name: "kiosk",
+			GarbageCollect: &config.GarbageCollectionPolicy{
+				UnseenDevicesDays: days(0), // never removed for being unseen
+			},
+			Folder: []*config.FolderPattern{
+				{Id: "${name}", GarbageCollect: &config.GarbageCollectionPolicy{Never: true}},
+			},
+		},
+		{
+			Name: "short",
+			GarbageCollect: &config.GarbageCollectionPolicy{
+				NeverSeenGraceDays: days(0), // never removed for never being seen
+			},
+		},
+	}
+	in.conf.GarbageCollect.Protect = []string{manualID.String(), "manual-*"}
+	in.managed.Devices[unseenID] = state.Device{Pattern: "kiosk"}
+	in.managed.Devices[neverID] = state.Device{Pattern: "short", Accepted: daysBefore(30)}
+	in.managed.Folders["orphan"] = state.Folder{Pattern: "kiosk", FolderPattern: "${name}"}
+
+	p := makePlan(in)
+
+	// The only device candidate left is the protected manual device
+	if len(p.Devices) != 1 || p.Devices[0].ID != manualID.String() || !p.Devices[0].Protected {
+		t.Errorf("got device candidates %+v, want only protected manual device", p.Devices)
+	}
+	// Both folders are candidates, but protected
+	for _, fld := range p.Folders {
+		if !fld.Protected {
+			t.Errorf("folder %s is not protected", fld.ID)
+		}
+	}
+	if devices, folders, _ := p.Removals(); devices != 0 || folders != 0 {
+		t.Errorf("got %d device and %d folder removals, want none", devices, folders)
+	}
+}
+
+func TestPlanDeclared(t *testing.T) {
+	t.Parallel()
+
+	in := testPlanInput()
+	in.conf.Device = []*config.DesiredDevice{{Id: unseenID.String()}}
+	in.conf.Folder = []*config.DesiredFolder{{Id: "orphan", Settings: &config.FolderConfiguration{Path: "/var/device-folders/orphan"}}}
+	p := makePlan(in)
+
+	for _, cand := range append(p.Devices, p.Folders...) {
+		declared := cand.ID == unseenID.String() || cand.ID == "orphan"
+		if cand.Protected != declared {
+			t.Errorf("%s protected = %v, want %v", cand.ID, cand.Protected, declared)
+		}
+	}
+}
+
+func TestPlanLimits(t *testing.T) {
+	t.Parallel()
+
+	p := makePlan(testPlanInput())
+
+	cases := []struct {
+		gc *config.GarbageCollection
+		ok bool
+	}{
+		{&config.GarbageCollection{}, true},
+		{&config.GarbageCollection{MaxRemovalsPerRun: 6}, true},
+		{&config.GarbageCollection{MaxRemovalsPerRun: 5}, false},
+		{&config.GarbageCollection{MaxRemovalFraction: 0.75}, true},
+		{&config.GarbageCollection{MaxRemovalFraction: 0.5}, false}, // 3 of 5 devices
+	}
+	for _, c := range cases {
+		err := p.CheckLimits(c.gc)
+		if c.ok && err != nil {
+			t.Errorf("CheckLimits(%v) returned error: %v", c.gc, err)
+		} else if !c.ok && err == nil {
+			t.Errorf("CheckLimits(%v) returned nil error", c.gc)
+		}
+	}
+}
+
+func TestPlanStaleShares(t *testing.T) {
+	t.Parallel()
+
+	in := testPlanInput()
+	days := func(n int32) *int32 { return &n }
+	in.conf.GarbageCollect.StaleSharesDays = 30
+	in.conf.Pattern = []*config.DevicePattern{
+		{Name: "kiosk", GarbageCollect: &config.GarbageCollectionPolicy{StaleSharesDays: days(0)}},
+	}
+	in.managed.Devices[seenID] = state.Device{Pattern: "kiosk", Accepted: daysBefore(200)}
+	in.shares = map[string]map[protocol.DeviceID]time.Time{
+		"default": {
+			seenID:   daysBefore(60), // stale, but the pattern disables it
+			unseenID: daysBefore(60), // stale, but the device is removed anyway
+			neverID:  daysBefore(10),
+			newID:    daysBefore(31), // stale
+			manualID: daysBefore(60), // stale, but the device is removed anyway
+		},
+		"never": {neverID: daysBefore(60)}, // the folder is removed with the device
+	}
+
+	p := makePlan(in)
+	var got []string
+	for _, c := range p.Shares {
+		got = append(got, c.ID+"/"+c.Device)
+	}
+	want := []string{"default/" + newID.String()}
+	if !slices.Equal(got, want) {
+		t.Errorf("got shares %v, want %v", got, want)
+	}
+	if p.TotalShares != 6 {
+		t.Errorf("got %d total shares, want 6", p.TotalShares)
+	}
+
+	// Only managed devices or folders
+	in.conf.GarbageCollect.OnlyManaged = true
+	in.conf.GarbageCollect.Protect = []string{newID.String()}
+	p = makePlan(in)
+	if len(p.Shares) != 1 || p.Shares[0].Device != newID.String() || !p.Shares[0].Protected {
+		t.Errorf("got shares %+v, want only protected share with new device", p.Shares)
+	}
+}
@@ -0,0 +1,113 @@
+package gc
+
+import (
+	"kastelo.dev/syncthing-configd/internal/config"
+	"kastelo.dev/syncthing-configd/internal/state"
+)
+
+// policy is the effective garbage collection policy for a device or
+// folder, after applying any overrides from the pattern that created it.
+type policy struct {
+	unseenDevicesDays  int
+	neverSeenGraceDays int
+	unsharedFolders    bool
+	never              bool
+	dataDisposition    string
+	staleSharesDays    int
+}
+
+func globalPolicy(gc *config.GarbageCollection) policy {
+	return policy{
+		unseenDevicesDays:  int(gc.GetUnseenDevicesDays()),
+		neverSeenGraceDays: int(gc.GetNeverSeenGraceDays()),
+		unsharedFolders:    gc.GetUnsharedFolders(),
+		dataDisposition:    gc.GetDataDisposition(),
+		staleSharesDays:    int(gc.GetStaleSharesDays()),
+	}
+}
+
+func (p policy) with(o *config.GarbageCollectionPolicy) policy {
+	if o == nil {
+		return p
+	}
+	if o.UnseenDevicesDays != nil {
+		p.unseenDevicesDays = int(*o.UnseenDevicesDays)
+	}
+	if o.NeverSeenGraceDays != nil {
+		p.neverSeenGraceDays = int(*o.NeverSeenGraceDays)
+	}
+	if o.UnsharedFolders != nil {
+		p.unsharedFolders = *o.UnsharedFolders
+	}
+	if o.DataDisposition != nil {
+		p.dataDisposition = *o.DataDisposition
+	}
+	if o.StaleSharesDays != nil {
+		p.staleSharesDays = int(*o.StaleSharesDays)
+	}
+	p.never = p.never || o.Never
+	return p
+}
+
+// devicePolicy returns the policy for a device created by the given
+// pattern, which may be empty for devices not created by configd.
+func devicePolicy(cfg *config.Configuration, pattern string) policy {
+	pol := globalPolicy(cfg.GetGarbageCollect())
+	if pat := cfg.PatternByName(pattern); pat != nil {
+		pol = pol.with(pat.GarbageCollect)
+	}
+	return pol
+}
+
+// folderPolicy returns the policy for a managed folder, or the global
+// policy if the folder wasn't created by configd.
+func folderPolicy(cfg *config.Configuration, fld state.Folder, managed bool) policy {
+	pol := globalPolicy(cfg.GetGarbageCollect())
+	if !managed {
+		return pol
+	}
+	pat := cfg.PatternByName(fld.Pattern)
+	if pat == nil {
+		return pol
+	}
+	pol = pol.with(pat.GarbageCollect)
+	for _, fp := range pat.Folder {
+		if fp.Id == fld.FolderPattern {
+			pol = pol.with(fp.GarbageCollect)
+			break
+		}
+	}
+	return pol
+}
+
+// sharePolicy returns the policy for sharing a folder with a device. The
+// policy of a managed folder takes precedence over that of the device, but
+// either can prevent the share from being removed.
+func sharePolicy(cfg *config.Configuration, fld state.Folder, fldManaged bool, dev state.Device) policy {
+	devPol := devicePolicy(cfg, dev.Pattern)
+	if !fldManaged {
+		return devPol
+	}
+	pol := folderPolicy(cfg, fld, true)
+	pol.never = pol.never || devPol.never
+	return pol
+}
+
+// staleSharesEnabled returns true if stale shares are removed globally or
+// by any pattern policy.
+func staleSharesEnabled(cfg *config.Configuration) bool {
+	if cfg.GetGarbageCollect().GetStaleSharesDays() > 0 {
+		return true
+	}
+	for _, pat := range cfg.GetPattern() {
+		if pat.GetGarbageCollect().GetStaleSharesDays() > 0 {
+			return true
+		}
+		for _, fp := range pat.GetFolder() {
+			if fp.GetGarbageCollect().GetStaleSharesDays() > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
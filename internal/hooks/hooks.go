@@ -0,0 +1,185 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+const (
+	defaultTimeout       = time.Minute
+	defaultMaxConcurrent = 4
+)
+
+const (
+	EventAccept    = "accept"
+	EventDeny      = "deny"
+	EventGCRemove  = "gc-remove"
+	EventGCUnshare = "gc-unshare" // runs the on_gc_remove hook
+	EventGCAlert   = "gc-alert"
+)
+
+// Payload describes the event that triggered a hook. It's passed to the
+// hook command as JSON on stdin, and as CONFIGD_* environment variables.
+type Payload struct {
+	Event    string   `json:"event"`
+	Instance string   `json:"instance"`
+	Device   string   `json:"device,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Address  string   `json:"address,omitempty"`
+	Pattern  string   `json:"pattern,omitempty"`
+	Folders  []string `json:"folders,omitempty"`
+	Path     string   `json:"path,omitempty"`
+	Message  string   `json:"message,omitempty"`
+}
+
+func (p *Payload) environ() []string {
+	env := []string{
+		"CONFIGD_EVENT=" + p.Event,
+		"CONFIGD_INSTANCE=" + p.Instance,
+	}
+	if p.Device != "" {
+		env = append(env, "CONFIGD_DEVICE="+p.Device)
+	}
+	if p.Name != "" {
+		env = append(env, "CONFIGD_NAME="+p.Name)
+	}
+	if p.Address != "" {
+		env = append(env, "CONFIGD_ADDRESS="+p.Address)
+	}
+	if p.Pattern != "" {
+		env = append(env, "CONFIGD_PATTERN="+p.Pattern)
+	}
+	if len(p.Folders) > 0 {
+		env = append(env, "CONFIGD_FOLDERS="+strings.Join(p.Folders, " "))
+	}
+	if p.Path != "" {
+		env = append(env, "CONFIGD_PATH="+p.Path)
+	}
+	if p.Message != "" {
+		env = append(env, "CONFIGD_MESSAGE="+p.Message)
+	}
+	return env
+}
+
+// Runner executes configured hook commands, limiting the number of
+// commands running concurrently.
+type Runner struct {
+	log *slog.Logger
+	cfg *config.Hooks
+	sem chan struct{}
+	wg  sync.WaitGroup
+}
+
+func NewRunner(log *slog.Logger, cfg *config.Hooks) *Runner {
+	maxConcurrent := int(cfg.GetMaxConcurrent())
+	if maxConcurrent <= 0 {
+		maxConcurrent = defaultMaxConcurrent
+	}
+	return &Runner{
+		log: log,
+		cfg: cfg,
+		sem: make(chan struct{}, maxConcurrent),
+	}
+}
+
+// Hook returns the configured hook for the given event, or nil.
+func (r *Runner) Hook(event string) *config.Hook {
+	if r == nil {
+		return nil
+	}
+	switch event {
+	case EventAccept:
+		return r.cfg.GetOnAccept()
+	case EventDeny:
+		return r.cfg.GetOnDeny()
+	case EventGCRemove, EventGCUnshare:
+		return r.cfg.GetOnGcRemove()
+	case EventGCAlert:
+		return r.cfg.GetOnGcAlert()
+	}
+	return nil
+}
+
+// Run executes the hook for the payload's event and waits for it to
+// complete. An unconfigured hook is a successful no-op.
+func (r *Runner) Run(ctx context.Context, p *Payload) error {
+	hook := r.Hook(p.Event)
+	if len(hook.GetCommand()) == 0 {
+		return nil
+	}
+
+	l := r.log.With("hook", p.Event, "command", hook.Command[0])
+
+	select {
+	case r.sem <- struct{}{}:
+		defer func() { <-r.sem }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	timeout := time.Duration(hook.TimeoutS) * time.Second
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	input, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, hook.Command[0], hook.Command[1:]...)
+	cmd.Env = append(os.Environ(), p.environ()...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.WaitDelay = time.Second
+
+	l.Debug("Running hook")
+	t0 := time.Now()
+	out, err := cmd.CombinedOutput()
+	l = l.With("duration", time.Since(t0).Round(time.Millisecond))
+	if len(out) > 0 {
+		l.Info("Hook output", "output", string(bytes.TrimSpace(out)))
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %v", timeout)
+	}
+	if err != nil {
+		l.Error("Hook failed", "error", err)
+		return fmt.Errorf("hook %s: %w", p.Event, err)
+	}
+	l.Debug("Hook completed")
+	return nil
+}
+
+// Go runs the hook for the payload's event in the background, logging but
+// otherwise ignoring any failure.
+func (r *Runner) Go(ctx context.Context, p *Payload) {
+	if len(r.Hook(p.Event).GetCommand()) == 0 {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		_ = r.Run(ctx, p)
+	}()
+}
+
+// Wait waits for all hooks started by Go to complete.
+func (r *Runner) Wait() {
+	if r == nil {
+		return
+	}
+	r.wg.Wait()
+}
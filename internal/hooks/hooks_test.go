@@ -0,0 +1,67 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+func TestRunHook(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "out")
+	r := NewRunner(slog.Default(), &config.Hooks{
+		OnAccept: &config.Hook{
+			Command: []string{"sh", "-c", `cat > "$0.json" && echo "$CONFIGD_EVENT $CONFIGD_DEVICE $CONFIGD_FOLDERS" > "$0"`, out},
+		},
+		OnDeny: &config.Hook{
+			Command:  []string{"sleep", "5"},
+			TimeoutS: 1,
+		},
+	})
+
+	p := &Payload{
+		Event:    EventAccept,
+		Instance: "127.0.0.1:8384",
+		Device:   "ABC",
+		Folders:  []string{"default", "abc"},
+	}
+	if err := r.Run(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+
+	bs, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(bs), "accept ABC default abc\n"; got != want {
+		t.Errorf("got environment %q, want %q", got, want)
+	}
+
+	bs, err = os.ReadFile(out + ".json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Payload
+	if err := json.Unmarshal(bs, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Device != p.Device || got.Instance != p.Instance || len(got.Folders) != 2 {
+		t.Errorf("got payload %+v, want %+v", got, p)
+	}
+
+	// The deny hook times out
+	if err := r.Run(context.Background(), &Payload{Event: EventDeny}); err == nil {
+		t.Error("unexpected nil error for timed out hook")
+	}
+
+	// The GC hook isn't configured, which is fine
+	if err := r.Run(context.Background(), &Payload{Event: EventGCRemove}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,240 @@
+// Package reconcile makes sure the devices and folders declared in the
+// configuration exist, with the declared settings, on each instance, and
+// that the declared options are in effect.
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/api"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+const (
+	defaultInterval = 5 * time.Minute
+	retryInterval   = 10 * time.Second
+)
+
+type Reconciler struct {
+	log      *slog.Logger
+	api      *api.API
+	cfg      *config.Configuration
+	instance *config.SyncthingInstance
+	trigger  chan struct{}
+}
+
+// New returns a Reconciler for the devices and folders whose instance
+// selector matches the instance.
+func New(log *slog.Logger, api *api.API, cfg *config.Configuration, instance *config.SyncthingInstance) *Reconciler {
+	return &Reconciler{
+		log:      log.With("address", api.Address()),
+		api:      api,
+		cfg:      cfg,
+		instance: instance,
+		trigger:  make(chan struct{}, 1),
+	}
+}
+
+// Serve reconciles at startup, periodically, and whenever Syncthing's
+// configuration is saved.
+func (r *Reconciler) Serve(ctx context.Context) error {
+	interval := time.Duration(r.cfg.ReconcileIntervalS) * time.Second
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	go r.watchConfig(ctx)
+
+	for {
+		wait := interval
+		if err := r.run(); err != nil {
+			r.log.Error("Failed to reconcile devices and folders", "error", err)
+			wait = retryInterval
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		case <-r.trigger:
+			r.log.Debug("Reconciling after configuration change")
+		}
+	}
+}
+
+func (r *Reconciler) String() string {
+	return fmt.Sprintf("reconciler(%s)@%p", r.api.Address(), r)
+}
+
+// watchConfig triggers a run for every ConfigSaved event, including those
+// caused by the reconciler itself; the run after those finds nothing to
+// do.
+func (r *Reconciler) watchConfig(ctx context.Context) {
+	es := r.api.Events([]events.EventType{events.ConfigSaved})
+	for {
+		evs, err := es.Events(ctx)
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retryInterval):
+			}
+			continue
+		}
+		if len(evs) > 0 {
+			select {
+			case r.trigger <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (r *Reconciler) run() error {
+	return r.api.InConfigTx(func(tx *api.ConfigTx) error {
+		status, err := tx.GetSystemStatus()
+		if err != nil {
+			return err
+		}
+		r.reconcileOptions(tx)
+
+		created, updated := 0, 0
+		count := func(existed bool) {
+			if existed {
+				updated++
+			} else {
+				created++
+			}
+		}
+
+		known := make(map[protocol.DeviceID]bool)
+		for _, dev := range tx.Config.Devices {
+			known[dev.DeviceID] = true
+		}
+
+		for _, want := range r.cfg.Device {
+			if !r.instance.Matches(want.InstanceSelector) {
+				continue
+			}
+			id, err := protocol.DeviceIDFromString(want.Id)
+			if err != nil || id == status.MyID {
+				continue
+			}
+			cur, _, exists := tx.Config.Device(id)
+			dev, changed := desiredDevice(cur, exists, tx.Config.Defaults.Device, want, id)
+			if !changed {
+				continue
+			}
+			l := r.log.With("device", id, "name", dev.Name)
+			if err := tx.PutDevice(dev); err != nil {
+				l.Error("Failed to reconcile device", "error", err)
+				continue
+			}
+			l.Info("Reconciled device", "created", !exists)
+			known[id] = true
+			count(exists)
+		}
+
+		for _, want := range r.cfg.Folder {
+			if !r.instance.Matches(want.InstanceSelector) {
+				continue
+			}
+			cur, _, exists := tx.Config.Folder(want.Id)
+			fld, changed := desiredFolder(cur, exists, tx.Config.Defaults.Folder, want, func(id protocol.DeviceID) bool {
+				if !known[id] {
+					r.log.Warn("Not sharing folder with unknown device", "folder", want.Id, "device", id)
+				}
+				return known[id]
+			})
+			if !changed {
+				continue
+			}
+			l := r.log.With("folder", fld.ID, "path", fld.Path)
+			if err := tx.PutFolder(fld); err != nil {
+				l.Error("Failed to reconcile folder", "error", err)
+				continue
+			}
+			l.Info("Reconciled folder", "created", !exists)
+			count(exists)
+		}
+
+		if created+updated > 0 {
+			r.log.Info("Reconciled devices and folders", "created", created, "updated", updated)
+		}
+		return nil
+	})
+}
+
+// reconcileOptions applies the declared global options and GUI settings.
+func (r *Reconciler) reconcileOptions(tx *api.ConfigTx) {
+	opts := tx.Config.Options
+	if changed := r.cfg.Options.Apply(&opts); len(changed) > 0 {
+		if err := tx.PutOptions(opts); err != nil {
+			r.log.Error("Failed to set options", "options", changed, "error", err)
+		} else {
+			r.log.Info("Set options", "options", changed)
+		}
+	}
+	gui := tx.Config.GUI
+	if changed := r.cfg.GetOptions().GetGui().Apply(&gui); len(changed) > 0 {
+		if err := tx.PutGUI(gui); err != nil {
+			r.log.Error("Failed to set GUI settings", "settings", changed, "error", err)
+		} else {
+			r.log.Info("Set GUI settings", "settings", changed)
+		}
+	}
+}
+
+// desiredDevice returns the device configuration with the declared
+// settings applied to the current configuration, or to Syncthing's
+// defaults if the device doesn't exist, and whether that differs from the
+// current configuration.
+func desiredDevice(cur stconfig.DeviceConfiguration, exists bool, defaults stconfig.DeviceConfiguration, want *config.DesiredDevice, id protocol.DeviceID) (stconfig.DeviceConfiguration, bool) {
+	if !exists {
+		cur = defaults
+		cur.DeviceID = id
+	}
+	res := cur
+	res.Addresses = append([]string(nil), cur.Addresses...)
+	config.Overlay(&res, want.Settings.SyncthingConfig(), (*config.DeviceConfiguration)(nil).SyncthingConfig())
+	if want.Name != "" {
+		res.Name = want.Name
+	}
+	return res, !exists || !reflect.DeepEqual(res, cur)
+}
+
+// desiredFolder is like desiredDevice, for folders. The folder is also
+// shared with every declared device for which share returns true.
+func desiredFolder(cur stconfig.FolderConfiguration, exists bool, defaults stconfig.FolderConfiguration, want *config.DesiredFolder, share func(protocol.DeviceID) bool) (stconfig.FolderConfiguration, bool) {
+	if !exists {
+		cur = defaults
+		cur.ID = want.Id
+	}
+	res := cur
+	res.Devices = append([]stconfig.FolderDeviceConfiguration(nil), cur.Devices...)
+	config.Overlay(&res, want.Settings.SyncthingConfig(), (*config.FolderConfiguration)(nil).SyncthingConfig())
+
+nextDevice:
+	for _, devStr := range want.Device {
+		id, err := protocol.DeviceIDFromString(devStr)
+		if err != nil {
+			continue
+		}
+		for _, dev := range res.Devices {
+			if dev.DeviceID == id {
+				continue nextDevice
+			}
+		}
+		if share(id) {
+			res.Devices = append(res.Devices, stconfig.FolderDeviceConfiguration{DeviceID: id})
+		}
+	}
+	return res, !exists || !reflect.DeepEqual(res, cur)
+}
@@ -0,0 +1,145 @@
+package reconcile
+
+import (
+	"testing"
+
+	stconfig "github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"kastelo.dev/syncthing-configd/internal/config"
+)
+
+var (
+	dev1 = protocol.DeviceID{1}
+	dev2 = protocol.DeviceID{2}
+)
+
+func TestDesiredDevice(t *testing.T) {
+	t.Parallel()
+
+	defaults := stconfig.DeviceConfiguration{Addresses: []string{"dynamic"}, MaxSendKbps: 10}
+	cur := stconfig.DeviceConfiguration{DeviceID: dev1, Name: "old", Addresses: []string{"tcp://a"}, MaxRecvKbps: 20}
+
+	cases := []struct {
+		name    string
+		cur     stconfig.DeviceConfiguration
+		exists  bool
+		want    *config.DesiredDevice
+		res     stconfig.DeviceConfiguration
+		changed bool
+	}{
+		{
+			name:    "created from defaults",
+			want:    &config.DesiredDevice{Id: dev1.String(), Name: "new"},
+			res:     stconfig.DeviceConfiguration{DeviceID: dev1, Name: "new", Addresses: []string{"dynamic"}, MaxSendKbps: 10},
+			changed: true,
+		},
+		{
+			name:   "unchanged without settings",
+			cur:    cur,
+			exists: true,
+			want:   &config.DesiredDevice{Id: dev1.String()},
+			res:    cur,
+		},
+		{
+			name:   "declared settings already in place",
+			cur:    cur,
+			exists: true,
+			want:   &config.DesiredDevice{Id: dev1.String(), Name: "old", Settings: &config.DeviceConfiguration{MaxRecvKbps: 20}},
+			res:    cur,
+		},
+		{
+			name:    "only declared settings are corrected",
+			cur:     cur,
+			exists:  true,
+			want:    &config.DesiredDevice{Id: dev1.String(), Settings: &config.DeviceConfiguration{Addresses: []string{"tcp://b"}, MaxSendKbps: 5}},
+			res:     stconfig.DeviceConfiguration{DeviceID: dev1, Name: "old", Addresses: []string{"tcp://b"}, MaxSendKbps: 5, MaxRecvKbps: 20},
+			changed: true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			res, changed := desiredDevice(tc.cur, tc.exists, defaults, tc.want, dev1)
+			if changed != tc.changed {
+				t.Errorf("changed = %v, want %v", changed, tc.changed)
+			}
+			if res.Name != tc.res.Name || res.MaxSendKbps != tc.res.MaxSendKbps || res.MaxRecvKbps != tc.res.MaxRecvKbps || res.DeviceID != tc.res.DeviceID {
+				t.Errorf("got %+v, want %+v", res, tc.res)
+			}
+			if len(res.Addresses) != len(tc.res.Addresses) || len(res.Addresses) > 0 && res.Addresses[0] != tc.res.Addresses[0] {
+				t.Errorf("addresses = %v, want %v", res.Addresses, tc.res.Addresses)
+			}
+		})
+	}
+}
+
+func TestDesiredFolder(t *testing.T) {
+	t.Parallel()
+
+	defaults := stconfig.FolderConfiguration{RescanIntervalS: 3600, FSWatcherEnabled: true}
+	cur := stconfig.FolderConfiguration{
+		ID:               "photos",
+		Path:             "/data/photos",
+		RescanIntervalS:  60,
+		FSWatcherEnabled: true,
+		Devices:          []stconfig.FolderDeviceConfiguration{{DeviceID: dev1}},
+	}
+	known := func(id protocol.DeviceID) bool { return id == dev1 || id == dev2 }
+
+	t.Run("created from defaults", func(t *testing.T) {
+		t.Parallel()
+		want := &config.DesiredFolder{Id: "docs", Settings: &config.FolderConfiguration{Path: "/data/docs"}, Device: []string{dev1.String()}}
+		res, changed := desiredFolder(stconfig.FolderConfiguration{}, false, defaults, want, known)
+		if !changed {
+			t.Error("expected change")
+		}
+		if res.ID != "docs" || res.Path != "/data/docs" || res.RescanIntervalS != 3600 || !res.FSWatcherEnabled {
+			t.Errorf("unexpected folder %+v", res)
+		}
+		if len(res.Devices) != 1 || res.Devices[0].DeviceID != dev1 {
+			t.Errorf("unexpected devices %v", res.Devices)
+		}
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		t.Parallel()
+		want := &config.DesiredFolder{Id: "photos", Settings: &config.FolderConfiguration{Path: "/data/photos"}, Device: []string{dev1.String()}}
+		if _, changed := desiredFolder(cur, true, defaults, want, known); changed {
+			t.Error("unexpected change")
+		}
+	})
+
+	t.Run("devices added, never removed", func(t *testing.T) {
+		t.Parallel()
+		want := &config.DesiredFolder{Id: "photos", Device: []string{dev2.String()}}
+		res, changed := desiredFolder(cur, true, defaults, want, known)
+		if !changed {
+			t.Error("expected change")
+		}
+		if len(res.Devices) != 2 || res.Devices[0].DeviceID != dev1 || res.Devices[1].DeviceID != dev2 {
+			t.Errorf("unexpected devices %v", res.Devices)
+		}
+		if len(cur.Devices) != 1 {
+			t.Error("current configuration modified")
+		}
+	})
+
+	t.Run("unknown devices skipped", func(t *testing.T) {
+		t.Parallel()
+		want := &config.DesiredFolder{Id: "photos", Device: []string{protocol.DeviceID{3}.String()}}
+		if _, changed := desiredFolder(cur, true, defaults, want, known); changed {
+			t.Error("unexpected change")
+		}
+	})
+
+	t.Run("drifted setting corrected", func(t *testing.T) {
+		t.Parallel()
+		want := &config.DesiredFolder{Id: "photos", Settings: &config.FolderConfiguration{RescanIntervalS: 120}}
+		res, changed := desiredFolder(cur, true, defaults, want, known)
+		if !changed || res.RescanIntervalS != 120 || res.Path != "/data/photos" {
+			t.Errorf("unexpected folder %+v (changed %v)", res, changed)
+		}
+	})
+}
@@ -0,0 +1,302 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// Device is a device that was added to Syncthing by configd.
+type Device struct {
+	Pattern  string    `json:"pattern"`
+	Name     string    `json:"name,omitempty"`
+	Address  string    `json:"address,omitempty"`
+	Accepted time.Time `json:"accepted"`
+	Expires  time.Time `json:"expires,omitempty"` // when the pattern's ttl passes
+	Paused   bool      `json:"paused,omitempty"`  // paused by configd on expiry
+}
+
+// Folder is a folder that was created in Syncthing by configd.
+type Folder struct {
+	Pattern       string            `json:"pattern"`
+	FolderPattern string            `json:"folderPattern,omitempty"` // the folder ID template
+	Device        protocol.DeviceID `json:"device"`                  // the device the folder was created for
+	Path          string            `json:"path,omitempty"`
+	Created       time.Time         `json:"created"`
+}
+
+// Quarantined is the data directory of a removed folder, awaiting
+// deletion.
+type Quarantined struct {
+	Folder  string    `json:"folder"`
+	Removed time.Time `json:"removed"`
+	Due     time.Time `json:"due"`
+}
+
+// Instance holds the managed objects of one Syncthing instance.
+type Instance struct {
+	Devices    map[protocol.DeviceID]Device `json:"devices"`
+	Folders    map[string]Folder            `json:"folders"`
+	Quarantine map[string]Quarantined       `json:"quarantine,omitempty"` // by path
+	// Shares holds the time each folder was last seen actively shared with
+	// each device, by folder ID and device ID.
+	Shares map[string]map[protocol.DeviceID]time.Time `json:"shares,omitempty"`
+}
+
+// Store keeps track of the devices and folders configd has created, per
+// Syncthing instance (identified by its device ID). The state is persisted
+// to disk on every change, unless the store was opened without a path.
+type Store struct {
+	mut       sync.Mutex
+	path      string
+	instances map[protocol.DeviceID]*Instance
+}
+
+// Open loads the store from the given path. A missing file results in an
+// empty store, and an empty path in a store that is only kept in memory.
+func Open(path string) (*Store, error) {
+	s := &Store{
+		path:      path,
+		instances: make(map[protocol.DeviceID]*Instance),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	bs, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading state: %w", err)
+	}
+	if err := json.Unmarshal(bs, &s.instances); err != nil {
+		return nil, fmt.Errorf("parsing state: %w", err)
+	}
+	return s, nil
+}
+
+func (s *Store) AddDevice(instance, id protocol.DeviceID, dev Device) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.instanceLocked(instance).Devices[id] = dev
+	return s.saveLocked()
+}
+
+// UpdateDevice calls fn to modify a recorded device, returning false if
+// there is no such device.
+func (s *Store) UpdateDevice(instance, id protocol.DeviceID, fn func(*Device)) (bool, error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	inst, ok := s.instances[instance]
+	if !ok {
+		return false, nil
+	}
+	dev, ok := inst.Devices[id]
+	if !ok {
+		return false, nil
+	}
+	fn(&dev)
+	inst.Devices[id] = dev
+	return true, s.saveLocked()
+}
+
+// AddFolder records a created folder. If the folder's path was in
+// quarantine, awaiting deletion, it is taken out of quarantine.
+func (s *Store) AddFolder(instance protocol.DeviceID, id string, fld Folder) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	inst := s.instanceLocked(instance)
+	inst.Folders[id] = fld
+	delete(inst.Quarantine, fld.Path)
+	return s.saveLocked()
+}
+
+// Quarantine records that the data at path should be deleted once the due
+// time has passed.
+func (s *Store) Quarantine(instance protocol.DeviceID, path string, q Quarantined) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	inst := s.instanceLocked(instance)
+	if inst.Quarantine == nil {
+		inst.Quarantine = make(map[string]Quarantined)
+	}
+	inst.Quarantine[path] = q
+	return s.saveLocked()
+}
+
+func (s *Store) Unquarantine(instance protocol.DeviceID, path string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	inst, ok := s.instances[instance]
+	if !ok {
+		return nil
+	}
+	if _, ok := inst.Quarantine[path]; !ok {
+		return nil
+	}
+	delete(inst.Quarantine, path)
+	return s.saveLocked()
+}
+
+// SetShares replaces the recorded share activity for the instance.
+func (s *Store) SetShares(instance protocol.DeviceID, shares map[string]map[protocol.DeviceID]time.Time) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.instanceLocked(instance).Shares = copyShares(shares)
+	return s.saveLocked()
+}
+
+func (s *Store) Device(instance, id protocol.DeviceID) (Device, bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	inst, ok := s.instances[instance]
+	if !ok {
+		return Device{}, false
+	}
+	dev, ok := inst.Devices[id]
+	return dev, ok
+}
+
+func (s *Store) Folder(instance protocol.DeviceID, id string) (Folder, bool) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	inst, ok := s.instances[instance]
+	if !ok {
+		return Folder{}, false
+	}
+	fld, ok := inst.Folders[id]
+	return fld, ok
+}
+
+func (s *Store) RemoveDevice(instance, id protocol.DeviceID) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	inst, ok := s.instances[instance]
+	if !ok {
+		return nil
+	}
+	if _, ok := inst.Devices[id]; !ok {
+		return nil
+	}
+	delete(inst.Devices, id)
+	return s.saveLocked()
+}
+
+func (s *Store) RemoveFolder(instance protocol.DeviceID, id string) error {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	inst, ok := s.instances[instance]
+	if !ok {
+		return nil
+	}
+	if _, ok := inst.Folders[id]; !ok {
+		return nil
+	}
+	delete(inst.Folders, id)
+	return s.saveLocked()
+}
+
+// Instance returns a copy of the managed objects for the given instance.
+func (s *Store) Instance(id protocol.DeviceID) Instance {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	return copyInstance(s.instances[id])
+}
+
+// Instances returns a copy of the managed objects for all instances.
+func (s *Store) Instances() map[protocol.DeviceID]Instance {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	res := make(map[protocol.DeviceID]Instance, len(s.instances))
+	for id, inst := range s.instances {
+		res[id] = copyInstance(inst)
+	}
+	return res
+}
+
+func copyInstance(inst *Instance) Instance {
+	cp := Instance{
+		Devices: make(map[protocol.DeviceID]Device),
+		Folders: make(map[string]Folder),
+	}
+	if inst == nil {
+		return cp
+	}
+	for k, v := range inst.Devices {
+		cp.Devices[k] = v
+	}
+	for k, v := range inst.Folders {
+		cp.Folders[k] = v
+	}
+	if len(inst.Quarantine) > 0 {
+		cp.Quarantine = make(map[string]Quarantined, len(inst.Quarantine))
+		for k, v := range inst.Quarantine {
+			cp.Quarantine[k] = v
+		}
+	}
+	cp.Shares = copyShares(inst.Shares)
+	return cp
+}
+
+func copyShares(shares map[string]map[protocol.DeviceID]time.Time) map[string]map[protocol.DeviceID]time.Time {
+	if len(shares) == 0 {
+		return nil
+	}
+	cp := make(map[string]map[protocol.DeviceID]time.Time, len(shares))
+	for fld, devs := range shares {
+		cp[fld] = make(map[protocol.DeviceID]time.Time, len(devs))
+		for dev, t := range devs {
+			cp[fld][dev] = t
+		}
+	}
+	return cp
+}
+
+func (s *Store) instanceLocked(id protocol.DeviceID) *Instance {
+	inst, ok := s.instances[id]
+	if !ok {
+		inst = &Instance{
+			Devices: make(map[protocol.DeviceID]Device),
+			Folders: make(map[string]Folder),
+		}
+		s.instances[id] = inst
+	}
+	return inst
+}
+
+func (s *Store) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+
+	bs, err := json.MarshalIndent(s.instances, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it into place, so that we never
+	// leave a partially written state file behind.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(bs); err != nil {
+		tmp.Close()
+		return fmt.Errorf("saving state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestStorePersistence(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "state.json")
+	inst := protocol.LocalDeviceID
+	dev := protocol.DeviceID{1, 2, 3}
+	now := time.Now().Truncate(time.Second)
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddDevice(inst, dev, Device{Pattern: "kiosk", Name: "test", Accepted: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddFolder(inst, "test", Folder{Pattern: "kiosk", Device: dev, Created: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddFolder(inst, "other", Folder{Pattern: "kiosk", Device: dev, Created: now}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RemoveFolder(inst, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := s.UpdateDevice(inst, dev, func(d *Device) { d.Expires = now.Add(time.Hour) }); !ok || err != nil {
+		t.Fatal("updating device:", ok, err)
+	}
+	if ok, _ := s.UpdateDevice(inst, protocol.DeviceID{9}, func(*Device) {}); ok {
+		t.Error("updated unknown device")
+	}
+	if err := s.SetShares(inst, map[string]map[protocol.DeviceID]time.Time{"test": {dev: now}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Load it again from disk
+	s, err = Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, ok := s.Device(inst, dev); !ok {
+		t.Error("device not found after reload")
+	} else if d.Pattern != "kiosk" || d.Name != "test" || !d.Accepted.Equal(now) || !d.Expires.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected device after reload: %+v", d)
+	}
+	if f, ok := s.Folder(inst, "test"); !ok {
+		t.Error("folder not found after reload")
+	} else if f.Device != dev {
+		t.Errorf("unexpected folder after reload: %+v", f)
+	}
+	if _, ok := s.Folder(inst, "other"); ok {
+		t.Error("removed folder found after reload")
+	}
+	if t0 := s.Instance(inst).Shares["test"][dev]; !t0.Equal(now) {
+		t.Errorf("unexpected share activity after reload: %v", t0)
+	}
+	if _, ok := s.Device(dev, dev); ok {
+		t.Error("device found for the wrong instance")
+	}
+}